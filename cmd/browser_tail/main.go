@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/ajsharma/browser_tail/internal/cdp"
 	"github.com/ajsharma/browser_tail/internal/config"
 	"github.com/ajsharma/browser_tail/internal/control"
+	"github.com/ajsharma/browser_tail/internal/events"
 	"github.com/ajsharma/browser_tail/internal/logger"
 )
 
@@ -54,6 +56,32 @@ func init() {
 		"Flush interval for log buffering")
 	rootCmd.Flags().Int("buffer-size", defaults.BufferSize,
 		"Buffer size per tab in bytes")
+	rootCmd.Flags().StringSlice("sink", defaults.Sinks,
+		"Where to send events; repeat or comma-separate for several (file, stdout)")
+	rootCmd.Flags().Bool("stdout", false,
+		"Stream events as JSON lines to stdout instead of log files (add --sink file to keep both)")
+	rootCmd.Flags().Int("rotate-max-size", defaults.RotateMaxSizeMB,
+		"Rotate session.log once it reaches this size in MB (0 to disable)")
+	rootCmd.Flags().Duration("rotate-max-age", defaults.RotateMaxAge,
+		"Rotate session.log once it has been open this long (0 to disable)")
+	rootCmd.Flags().Bool("rotate-on-start", defaults.RotateOnStart,
+		"Rotate session.log files left by a previous run")
+	rootCmd.Flags().String("rotate-naming", defaults.RotateNaming,
+		"Rotated file names: numbered (session.<n>.log) or timestamp")
+	rootCmd.Flags().String("compress", defaults.Compression,
+		"Compress closed and rotated logs: none, gzip or zstd")
+	rootCmd.Flags().Bool("compress-stream", defaults.CompressStream,
+		"Write logs compressed from the start instead of on close")
+
+	// Retention flags
+	rootCmd.Flags().Duration("retention-max-age", defaults.RetentionMaxAge,
+		"Remove log files older than this (0 to disable)")
+	rootCmd.Flags().Int("retention-max-size", defaults.RetentionMaxSizeMB,
+		"Remove the oldest log files while the output directory is over this size in MB (0 to disable)")
+	rootCmd.Flags().Int("retention-max-files", defaults.RetentionMaxFilesPerSite,
+		"Keep at most this many log files per site (0 to disable)")
+	rootCmd.Flags().Duration("retention-interval", defaults.RetentionInterval,
+		"Interval between retention passes")
 
 	// Privacy flags
 	rootCmd.Flags().BoolP("redact", "r", defaults.Redact,
@@ -72,12 +100,54 @@ func init() {
 		"Enable error events")
 	rootCmd.Flags().Bool("page", defaults.EnablePage,
 		"Enable page events")
+	rootCmd.Flags().Bool("websocket", defaults.EnableWebSocket,
+		"Enable WebSocket events")
+	rootCmd.Flags().Bool("workers", defaults.EnableWorkers,
+		"Enable web, shared and service worker monitoring")
+	rootCmd.Flags().Bool("frames", defaults.EnableFrames,
+		"Enable cross-origin iframe monitoring")
+	rootCmd.Flags().Bool("browser-log", defaults.EnableBrowserLog,
+		"Enable browser log events (CSP, violations, deprecations)")
+	rootCmd.Flags().Bool("audits", defaults.EnableAudits,
+		"Enable audit issue events (cookies, CORS, mixed content)")
+	rootCmd.Flags().Bool("web-vitals", defaults.EnableWebVitals,
+		"Enable Core Web Vitals events (injects a PerformanceObserver script)")
+	rootCmd.Flags().Bool("long-tasks", defaults.EnableLongTasks,
+		"Enable long task events (injects a PerformanceObserver script)")
+	rootCmd.Flags().Bool("storage", defaults.EnableStorage,
+		"Enable cookie and storage change events")
+	rootCmd.Flags().Bool("security", defaults.EnableSecurity,
+		"Enable TLS certificate and security state events")
+	rootCmd.Flags().Duration("perf-metrics-interval", defaults.PerfMetricsInterval,
+		"Interval between perf.metrics samples per tab (0 to disable)")
+	rootCmd.Flags().String("dialog-policy", defaults.DialogPolicy,
+		"How to answer JavaScript dialogs: manual, accept or dismiss")
+	rootCmd.Flags().Bool("downloads", defaults.EnableDownloads,
+		"Enable download events")
+	rootCmd.Flags().Bool("save-downloads", defaults.SaveDownloads,
+		"Save downloaded files under <output>/_downloads/<session>")
+
+	// Source map flags
+	rootCmd.Flags().Bool("source-maps", defaults.EnableSourceMaps,
+		"Symbolicate stack frames using source maps referenced by scripts")
+	rootCmd.Flags().String("source-map-dir", defaults.SourceMapDir,
+		"Directory of local .map files used to symbolicate stack frames")
 
 	// Add --no-* flags for disabling
 	rootCmd.Flags().Bool("no-network", false, "Disable network events")
 	rootCmd.Flags().Bool("no-console", false, "Disable console events")
 	rootCmd.Flags().Bool("no-errors", false, "Disable error events")
 	rootCmd.Flags().Bool("no-page", false, "Disable page events")
+	rootCmd.Flags().Bool("no-websocket", false, "Disable WebSocket events")
+	rootCmd.Flags().Bool("no-workers", false, "Disable worker monitoring")
+	rootCmd.Flags().Bool("no-frames", false, "Disable cross-origin iframe monitoring")
+	rootCmd.Flags().Bool("no-browser-log", false, "Disable browser log events")
+	rootCmd.Flags().Bool("no-audits", false, "Disable audit issue events")
+	rootCmd.Flags().Bool("no-web-vitals", false, "Disable Core Web Vitals events")
+	rootCmd.Flags().Bool("no-long-tasks", false, "Disable long task events")
+	rootCmd.Flags().Bool("no-downloads", false, "Disable download events")
+	rootCmd.Flags().Bool("no-storage", false, "Disable cookie and storage change events")
+	rootCmd.Flags().Bool("no-security", false, "Disable TLS certificate and security state events")
 	rootCmd.Flags().Bool("no-redact", false, "Disable redaction")
 
 	// Version flag
@@ -91,6 +161,9 @@ func init() {
 
 	// Add demo command
 	rootCmd.AddCommand(demoCmd)
+
+	// Add prune command
+	rootCmd.AddCommand(pruneCmd)
 }
 
 // Control command variables (PersistentFlags require pointer binding).
@@ -464,6 +537,105 @@ func runDemo(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old log files from the output directory",
+	Long: `Remove log files that fall outside the retention limits, oldest first:
+files older than --max-age, each site's oldest files beyond --max-files,
+then the oldest files until the output directory is under --max-size.
+
+Limits default to the retention settings in --config. While browser_tail is
+running on the same output directory, its active session.log files are
+skipped.
+
+Example:
+  browser_tail prune --max-age 168h
+  browser_tail prune --max-size 500 --dry-run
+  browser_tail prune --config config.yaml`,
+	RunE: runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().String("config", "", "Path to YAML config file")
+	pruneCmd.Flags().StringP("output", "o", "", "Output directory to prune (default from config, or ./logs)")
+	pruneCmd.Flags().Duration("max-age", 0, "Remove log files older than this")
+	pruneCmd.Flags().Int("max-size", 0, "Remove the oldest log files while over this size in MB")
+	pruneCmd.Flags().Int("max-files", 0, "Keep at most this many log files per site")
+	pruneCmd.Flags().Bool("dry-run", false, "List the files that would be removed without removing them")
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+
+	cfg := config.DefaultConfig()
+	if configFile != "" {
+		var err error
+		cfg, err = config.LoadFromFile(configFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config file: %w", err)
+		}
+	}
+
+	if cmd.Flags().Changed("output") {
+		cfg.OutputDir, _ = cmd.Flags().GetString("output")
+	}
+	if cmd.Flags().Changed("max-age") {
+		cfg.RetentionMaxAge, _ = cmd.Flags().GetDuration("max-age")
+	}
+	if cmd.Flags().Changed("max-size") {
+		cfg.RetentionMaxSizeMB, _ = cmd.Flags().GetInt("max-size")
+	}
+	if cmd.Flags().Changed("max-files") {
+		cfg.RetentionMaxFilesPerSite, _ = cmd.Flags().GetInt("max-files")
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	policy := retentionPolicy(cfg)
+	if !policy.Enabled() {
+		return fmt.Errorf("no retention limits set: use --max-age, --max-size or --max-files")
+	}
+
+	_, writerActive := logger.ActiveWriter(cfg.OutputDir)
+	if writerActive {
+		fmt.Printf("browser_tail is writing to %s; skipping active session.log files\n", cfg.OutputDir)
+	}
+
+	result, err := logger.Prune(cfg.OutputDir, policy, nil, dryRun)
+	if result == nil {
+		return fmt.Errorf("prune failed: %w", err)
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	for _, f := range result.Files {
+		fmt.Printf("%s %s (%s, %d bytes)\n", verb, f.Path, f.Reason, f.SizeBytes)
+	}
+	fmt.Printf("%s %d files, %d bytes\n", verb, len(result.Files), result.BytesFreed)
+
+	// The running process may have _meta/_session/session.log open, so the
+	// event is only written when it is not. Rotation and compression stay
+	// off: closing this FileManager must not move or compress the log.
+	if !dryRun && !writerActive && len(result.Files) > 0 {
+		fm := logger.NewFileManager(cfg.OutputDir)
+		if werr := fm.WriteEvent("_session", events.NewRetentionPrunedEvent(result)); werr != nil {
+			slog.Warn("Failed to write retention event", "error", werr)
+		}
+		if cerr := fm.Close(); cerr != nil {
+			slog.Warn("Failed to close log files", "error", cerr)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("some files could not be removed: %w", err)
+	}
+	return nil
+}
+
 // buildConfig constructs a Config from the command's flags, optionally loading
 // from a YAML file first. Flag values override file values; --no-* flags
 // override the positive counterparts.
@@ -497,6 +669,47 @@ func buildConfig(cmd *cobra.Command) (*config.Config, error) {
 	if cmd.Flags().Changed("buffer-size") {
 		cfg.BufferSize, _ = cmd.Flags().GetInt("buffer-size")
 	}
+	if cmd.Flags().Changed("sink") {
+		cfg.Sinks, _ = cmd.Flags().GetStringSlice("sink")
+	}
+	if stdout, _ := cmd.Flags().GetBool("stdout"); stdout {
+		switch {
+		case !cmd.Flags().Changed("sink"):
+			cfg.Sinks = []string{config.SinkStdout}
+		case !slices.Contains(cfg.Sinks, config.SinkStdout):
+			cfg.Sinks = append(cfg.Sinks, config.SinkStdout)
+		}
+	}
+	if cmd.Flags().Changed("rotate-max-size") {
+		cfg.RotateMaxSizeMB, _ = cmd.Flags().GetInt("rotate-max-size")
+	}
+	if cmd.Flags().Changed("rotate-max-age") {
+		cfg.RotateMaxAge, _ = cmd.Flags().GetDuration("rotate-max-age")
+	}
+	if cmd.Flags().Changed("rotate-on-start") {
+		cfg.RotateOnStart, _ = cmd.Flags().GetBool("rotate-on-start")
+	}
+	if cmd.Flags().Changed("rotate-naming") {
+		cfg.RotateNaming, _ = cmd.Flags().GetString("rotate-naming")
+	}
+	if cmd.Flags().Changed("compress") {
+		cfg.Compression, _ = cmd.Flags().GetString("compress")
+	}
+	if cmd.Flags().Changed("compress-stream") {
+		cfg.CompressStream, _ = cmd.Flags().GetBool("compress-stream")
+	}
+	if cmd.Flags().Changed("retention-max-age") {
+		cfg.RetentionMaxAge, _ = cmd.Flags().GetDuration("retention-max-age")
+	}
+	if cmd.Flags().Changed("retention-max-size") {
+		cfg.RetentionMaxSizeMB, _ = cmd.Flags().GetInt("retention-max-size")
+	}
+	if cmd.Flags().Changed("retention-max-files") {
+		cfg.RetentionMaxFilesPerSite, _ = cmd.Flags().GetInt("retention-max-files")
+	}
+	if cmd.Flags().Changed("retention-interval") {
+		cfg.RetentionInterval, _ = cmd.Flags().GetDuration("retention-interval")
+	}
 	if cmd.Flags().Changed("redact") {
 		cfg.Redact, _ = cmd.Flags().GetBool("redact")
 	}
@@ -518,6 +731,51 @@ func buildConfig(cmd *cobra.Command) (*config.Config, error) {
 	if cmd.Flags().Changed("page") {
 		cfg.EnablePage, _ = cmd.Flags().GetBool("page")
 	}
+	if cmd.Flags().Changed("websocket") {
+		cfg.EnableWebSocket, _ = cmd.Flags().GetBool("websocket")
+	}
+	if cmd.Flags().Changed("workers") {
+		cfg.EnableWorkers, _ = cmd.Flags().GetBool("workers")
+	}
+	if cmd.Flags().Changed("frames") {
+		cfg.EnableFrames, _ = cmd.Flags().GetBool("frames")
+	}
+	if cmd.Flags().Changed("browser-log") {
+		cfg.EnableBrowserLog, _ = cmd.Flags().GetBool("browser-log")
+	}
+	if cmd.Flags().Changed("audits") {
+		cfg.EnableAudits, _ = cmd.Flags().GetBool("audits")
+	}
+	if cmd.Flags().Changed("web-vitals") {
+		cfg.EnableWebVitals, _ = cmd.Flags().GetBool("web-vitals")
+	}
+	if cmd.Flags().Changed("long-tasks") {
+		cfg.EnableLongTasks, _ = cmd.Flags().GetBool("long-tasks")
+	}
+	if cmd.Flags().Changed("storage") {
+		cfg.EnableStorage, _ = cmd.Flags().GetBool("storage")
+	}
+	if cmd.Flags().Changed("security") {
+		cfg.EnableSecurity, _ = cmd.Flags().GetBool("security")
+	}
+	if cmd.Flags().Changed("perf-metrics-interval") {
+		cfg.PerfMetricsInterval, _ = cmd.Flags().GetDuration("perf-metrics-interval")
+	}
+	if cmd.Flags().Changed("dialog-policy") {
+		cfg.DialogPolicy, _ = cmd.Flags().GetString("dialog-policy")
+	}
+	if cmd.Flags().Changed("downloads") {
+		cfg.EnableDownloads, _ = cmd.Flags().GetBool("downloads")
+	}
+	if cmd.Flags().Changed("save-downloads") {
+		cfg.SaveDownloads, _ = cmd.Flags().GetBool("save-downloads")
+	}
+	if cmd.Flags().Changed("source-maps") {
+		cfg.EnableSourceMaps, _ = cmd.Flags().GetBool("source-maps")
+	}
+	if cmd.Flags().Changed("source-map-dir") {
+		cfg.SourceMapDir, _ = cmd.Flags().GetString("source-map-dir")
+	}
 
 	// --no-* flags always win
 	if noNetwork, _ := cmd.Flags().GetBool("no-network"); noNetwork {
@@ -532,6 +790,36 @@ func buildConfig(cmd *cobra.Command) (*config.Config, error) {
 	if noPage, _ := cmd.Flags().GetBool("no-page"); noPage {
 		cfg.EnablePage = false
 	}
+	if noWebSocket, _ := cmd.Flags().GetBool("no-websocket"); noWebSocket {
+		cfg.EnableWebSocket = false
+	}
+	if noWorkers, _ := cmd.Flags().GetBool("no-workers"); noWorkers {
+		cfg.EnableWorkers = false
+	}
+	if noFrames, _ := cmd.Flags().GetBool("no-frames"); noFrames {
+		cfg.EnableFrames = false
+	}
+	if noBrowserLog, _ := cmd.Flags().GetBool("no-browser-log"); noBrowserLog {
+		cfg.EnableBrowserLog = false
+	}
+	if noAudits, _ := cmd.Flags().GetBool("no-audits"); noAudits {
+		cfg.EnableAudits = false
+	}
+	if noWebVitals, _ := cmd.Flags().GetBool("no-web-vitals"); noWebVitals {
+		cfg.EnableWebVitals = false
+	}
+	if noLongTasks, _ := cmd.Flags().GetBool("no-long-tasks"); noLongTasks {
+		cfg.EnableLongTasks = false
+	}
+	if noDownloads, _ := cmd.Flags().GetBool("no-downloads"); noDownloads {
+		cfg.EnableDownloads = false
+	}
+	if noStorage, _ := cmd.Flags().GetBool("no-storage"); noStorage {
+		cfg.EnableStorage = false
+	}
+	if noSecurity, _ := cmd.Flags().GetBool("no-security"); noSecurity {
+		cfg.EnableSecurity = false
+	}
 	if noRedact, _ := cmd.Flags().GetBool("no-redact"); noRedact {
 		cfg.Redact = false
 	}
@@ -550,17 +838,25 @@ func run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create output directory
-	if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	if slices.Contains(cfg.Sinks, config.SinkFile) {
+		if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+
+		// Tell a standalone prune that the active logs are being written
+		removePIDFile, err := logger.WritePIDFile(cfg.OutputDir)
+		if err != nil {
+			slog.Warn("Failed to write PID file", "error", err)
+		} else {
+			defer removePIDFile()
+		}
 	}
 
-	// Create file manager
-	fm := logger.NewFileManager(cfg.OutputDir)
-	fm.SetFlushInterval(cfg.FlushInterval)
-	fm.SetBufferSize(cfg.BufferSize)
+	// Create output sinks
+	sink, fm := newSink(cfg)
 
 	// Create CDP manager
-	manager := cdp.NewManager(cfg, fm)
+	manager := cdp.NewManager(cfg, sink)
 
 	// Setup signal handling
 	ctx, cancel := context.WithCancel(context.Background())
@@ -580,7 +876,12 @@ func run(cmd *cobra.Command, args []string) error {
 	if cfg.AutoLaunch {
 		mode = "launching"
 	}
-	slog.Info("browser_tail starting", "version", config.Version, "output", cfg.OutputDir, "port", cfg.ChromePort, "mode", mode)
+	slog.Info("browser_tail starting", "version", config.Version, "output", cfg.OutputDir, "sinks", cfg.Sinks, "port", cfg.ChromePort, "mode", mode)
+
+	// Enforce retention in the background
+	if policy := retentionPolicy(cfg); fm != nil && policy.Enabled() {
+		go fm.RunRetention(ctx, policy, cfg.RetentionInterval)
+	}
 
 	// Start monitoring
 	errCh := make(chan error, 1)
@@ -603,6 +904,53 @@ func run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// newFileManager creates a FileManager with the output, rotation and
+// compression settings from cfg.
+func newFileManager(cfg *config.Config) *logger.FileManager {
+	fm := logger.NewFileManager(cfg.OutputDir)
+	fm.SetFlushInterval(cfg.FlushInterval)
+	fm.SetBufferSize(cfg.BufferSize)
+	fm.SetRotation(logger.RotationPolicy{
+		MaxBytes:    int64(cfg.RotateMaxSizeMB) * 1024 * 1024,
+		MaxAge:      cfg.RotateMaxAge,
+		OnStart:     cfg.RotateOnStart,
+		Timestamped: cfg.RotateNaming == config.RotateNamingTimestamp,
+	})
+	fm.SetCompression(cfg.Compression, cfg.CompressStream)
+	return fm
+}
+
+// newSink creates the sinks listed in cfg, fanning out to all of them when
+// there are several. The FileManager is returned separately, or nil without a
+// file sink, for retention.
+func newSink(cfg *config.Config) (logger.Sink, *logger.FileManager) {
+	var sinks []logger.Sink
+	var fm *logger.FileManager
+	for _, name := range cfg.Sinks {
+		switch name {
+		case config.SinkFile:
+			fm = newFileManager(cfg)
+			sinks = append(sinks, fm)
+		case config.SinkStdout:
+			sinks = append(sinks, logger.NewStreamSink(os.Stdout))
+		}
+	}
+
+	if len(sinks) == 1 {
+		return sinks[0], fm
+	}
+	return logger.NewFanOutSink(sinks...), fm
+}
+
+// retentionPolicy returns the retention limits from cfg.
+func retentionPolicy(cfg *config.Config) logger.RetentionPolicy {
+	return logger.RetentionPolicy{
+		MaxAge:          cfg.RetentionMaxAge,
+		MaxTotalBytes:   int64(cfg.RetentionMaxSizeMB) * 1024 * 1024,
+		MaxFilesPerSite: cfg.RetentionMaxFilesPerSite,
+	}
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
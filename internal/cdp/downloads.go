@@ -0,0 +1,109 @@
+package cdp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chromedp/cdproto/browser"
+	cdpproto "github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+
+	"github.com/ajsharma/browser_tail/internal/logger"
+	"github.com/ajsharma/browser_tail/internal/monitor"
+)
+
+// downloadResetTimeout bounds how long shutdown waits to restore Chrome's
+// download behavior.
+const downloadResetTimeout = 2 * time.Second
+
+// enableDownloads turns on browser-level download events. With SaveDownloads,
+// files are also redirected into the session's download directory.
+func (m *Manager) enableDownloads(browserCtx context.Context) error {
+	params := browser.SetDownloadBehavior(browser.SetDownloadBehaviorBehaviorDefault)
+	if m.config.SaveDownloads {
+		// Chrome requires an absolute download path
+		dir, err := filepath.Abs(logger.GetDownloadDir(m.config.OutputDir, m.tabRegistry.GetSessionID()))
+		if err != nil {
+			return fmt.Errorf("failed to resolve download directory: %w", err)
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create download directory: %w", err)
+		}
+		params = browser.SetDownloadBehavior(browser.SetDownloadBehaviorBehaviorAllow).WithDownloadPath(dir)
+		slog.Info("Saving downloads", "dir", dir)
+	}
+
+	// Download behavior is browser-wide, so it is set on the browser session
+	// rather than the anchor tab
+	c := chromedp.FromContext(browserCtx)
+	return params.WithEventsEnabled(true).Do(cdpproto.WithExecutor(browserCtx, c.Browser))
+}
+
+// resetDownloads restores Chrome's default download behavior, so files are
+// no longer redirected or reported once browser_tail exits. Errors are only
+// logged: Chrome may already be gone.
+func (m *Manager) resetDownloads(browserCtx context.Context) {
+	ctx, cancel := context.WithTimeout(browserCtx, downloadResetTimeout)
+	defer cancel()
+
+	c := chromedp.FromContext(browserCtx)
+	params := browser.SetDownloadBehavior(browser.SetDownloadBehaviorBehaviorDefault)
+	if err := params.Do(cdpproto.WithExecutor(ctx, c.Browser)); err != nil {
+		slog.Warn("Could not reset download behavior", "error", err)
+	}
+}
+
+// handleDownloadEvent routes a browser-level download event to the monitor
+// of the tab that started the download.
+func (m *Manager) handleDownloadEvent(ev interface{}) {
+	switch ev := ev.(type) {
+	case *browser.EventDownloadWillBegin:
+		mon := m.findFrameOwner(ev.FrameID.String())
+		if mon == nil {
+			slog.Debug("Ignoring download from unmonitored frame", "frame_id", ev.FrameID, "url", ev.URL)
+			return
+		}
+
+		m.downloadMu.Lock()
+		m.downloads[ev.GUID] = mon
+		m.downloadMu.Unlock()
+
+		mon.HandleDownloadWillBegin(ev)
+
+	case *browser.EventDownloadProgress:
+		m.downloadMu.Lock()
+		mon, exists := m.downloads[ev.GUID]
+		m.downloadMu.Unlock()
+
+		if !exists {
+			return
+		}
+
+		if mon.HandleDownloadProgress(ev) {
+			m.downloadMu.Lock()
+			delete(m.downloads, ev.GUID)
+			m.downloadMu.Unlock()
+		}
+	}
+}
+
+// findFrameOwner returns the monitor of the tab a frame belongs to, or nil.
+func (m *Manager) findFrameOwner(frameID string) *monitor.TabMonitor {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// A tab's main frame shares its target ID
+	if mon, exists := m.tabMonitors[frameID]; exists {
+		return mon
+	}
+	for _, mon := range m.tabMonitors {
+		if mon.OwnsFrame(frameID) {
+			return mon
+		}
+	}
+	return nil
+}
@@ -16,15 +16,22 @@ import (
 	"github.com/ajsharma/browser_tail/internal/events"
 	"github.com/ajsharma/browser_tail/internal/logger"
 	"github.com/ajsharma/browser_tail/internal/monitor"
+	"github.com/ajsharma/browser_tail/internal/sourcemap"
 )
 
 // Manager orchestrates CDP connections and tab monitoring.
 type Manager struct {
 	config           *config.Config
-	fileManager      *logger.FileManager
+	sink             logger.Sink
 	tabRegistry      *logger.TabRegistry
 	chromeProcess    *ChromeProcess
 	tabMonitors      map[string]*monitor.TabMonitor // targetID -> monitor
+	workerMonitors   map[string]*monitor.TabMonitor // targetID -> monitor
+	sourceMaps       *sourcemap.Cache               // shared across tabs; nil if disabled
+	downloads        map[string]*monitor.TabMonitor // download GUID -> monitor of the tab that started it
+	downloadMu       sync.Mutex
+	downloadsEnabled bool           // download behavior was changed and must be reset
+	stopping         sync.WaitGroup // monitors still writing queued events
 	mu               sync.RWMutex
 	allocatorCtx     context.Context
 	allocatorCancel  context.CancelFunc
@@ -41,13 +48,19 @@ const (
 )
 
 // NewManager creates a new CDP Manager.
-func NewManager(cfg *config.Config, fm *logger.FileManager) *Manager {
-	return &Manager{
-		config:      cfg,
-		fileManager: fm,
-		tabRegistry: logger.NewTabRegistry(),
-		tabMonitors: make(map[string]*monitor.TabMonitor),
+func NewManager(cfg *config.Config, sink logger.Sink) *Manager {
+	m := &Manager{
+		config:         cfg,
+		sink:           sink,
+		tabRegistry:    logger.NewTabRegistry(),
+		tabMonitors:    make(map[string]*monitor.TabMonitor),
+		workerMonitors: make(map[string]*monitor.TabMonitor),
+		downloads:      make(map[string]*monitor.TabMonitor),
 	}
+	if cfg.EnableSourceMaps || cfg.SourceMapDir != "" {
+		m.sourceMaps = sourcemap.NewCache(cfg.SourceMapDir)
+	}
+	return m
 }
 
 // Start begins monitoring Chrome with automatic reconnection.
@@ -63,7 +76,7 @@ func (m *Manager) Start(ctx context.Context) error {
 		// Wait for Chrome to be ready
 		if err := WaitForChrome(m.config.ChromePort, 30*time.Second); err != nil {
 			if stopErr := m.chromeProcess.Stop(); stopErr != nil {
-				log.Printf("Warning: failed to stop Chrome during cleanup: %v", stopErr)
+				slog.Warn("Failed to stop Chrome during cleanup", "error", stopErr)
 			}
 			return fmt.Errorf("chrome not ready: %w", err)
 		}
@@ -81,7 +94,7 @@ func (m *Manager) Start(ctx context.Context) error {
 		chromePID,
 		config.Version,
 	)
-	if err := m.fileManager.WriteEvent("_session", sessionEvent); err != nil {
+	if err := m.sink.WriteEvent("_session", sessionEvent); err != nil {
 		slog.Warn("Failed to write session start event", "error", err)
 	}
 
@@ -107,6 +120,7 @@ func (m *Manager) Start(ctx context.Context) error {
 			case <-m.browserCtx.Done():
 				// Browser disconnected, will retry
 				m.connected = false
+				m.allocatorCancel()
 				slog.Warn("Chrome disconnected, will retry", "wait", retryWait)
 			}
 		} else {
@@ -144,8 +158,10 @@ func (m *Manager) connect(ctx context.Context) error {
 	}
 
 	// Step 2: Connect to browser-level CDP
-	// Keep allocator context alive - it represents the browser connection
-	m.allocatorCtx, m.allocatorCancel = chromedp.NewRemoteAllocator(ctx, browserInfo.WebSocketDebuggerURL)
+	// Keep allocator context alive - it represents the browser connection.
+	// It outlives ctx so Stop can still restore browser settings on shutdown;
+	// Stop closes it.
+	m.allocatorCtx, m.allocatorCancel = chromedp.NewRemoteAllocator(context.WithoutCancel(ctx), browserInfo.WebSocketDebuggerURL)
 
 	// Create browser context
 	m.browserCtx, m.browserCancel = chromedp.NewContext(m.allocatorCtx)
@@ -189,6 +205,11 @@ func (m *Manager) connect(ctx context.Context) error {
 				m.handleNewTarget(ctx, ev.TargetInfo)
 			}
 
+			// Service and shared workers are not owned by a single tab
+			if m.config.EnableWorkers && isSharedWorkerType(ev.TargetInfo.Type) {
+				m.handleNewWorker(ctx, ev.TargetInfo)
+			}
+
 		case *target.EventTargetDestroyed:
 			// Skip destruction of our internal anchor
 			if string(ev.TargetID) != m.internalTargetID {
@@ -205,6 +226,17 @@ func (m *Manager) connect(ctx context.Context) error {
 		}
 	})
 
+	// Download events are browser-level, so they are not seen by tab listeners
+	m.downloadsEnabled = false
+	if m.config.EnableDownloads {
+		if err := m.enableDownloads(m.browserCtx); err != nil {
+			slog.Warn("Could not enable download events", "error", err)
+		} else {
+			m.downloadsEnabled = true
+			chromedp.ListenBrowser(m.browserCtx, m.handleDownloadEvent)
+		}
+	}
+
 	// Clean up on disconnect
 	go func() {
 		<-m.browserCtx.Done()
@@ -235,21 +267,41 @@ func (m *Manager) connect(ctx context.Context) error {
 	return nil
 }
 
-// clearTabMonitors stops and removes all tab monitors.
+// clearTabMonitors stops and removes all tab and worker monitors.
 func (m *Manager) clearTabMonitors() {
 	m.mu.Lock()
-	monitors := make([]*monitor.TabMonitor, 0, len(m.tabMonitors))
+	monitors := make([]*monitor.TabMonitor, 0, len(m.tabMonitors)+len(m.workerMonitors))
 	for _, mon := range m.tabMonitors {
 		monitors = append(monitors, mon)
 	}
+	for _, mon := range m.workerMonitors {
+		monitors = append(monitors, mon)
+	}
 	m.tabMonitors = make(map[string]*monitor.TabMonitor)
+	m.workerMonitors = make(map[string]*monitor.TabMonitor)
 	m.mu.Unlock()
 
+	m.downloadMu.Lock()
+	m.downloads = make(map[string]*monitor.TabMonitor)
+	m.downloadMu.Unlock()
+
 	for _, mon := range monitors {
-		mon.Stop()
+		m.stopMonitor(mon)
 	}
 }
 
+// stopMonitor stops a tab or worker monitor. Its queued events are written
+// in the background; Stop waits for them before closing the sink.
+func (m *Manager) stopMonitor(mon *monitor.TabMonitor) {
+	mon.Stop()
+
+	m.stopping.Add(1)
+	go func() {
+		defer m.stopping.Done()
+		mon.Wait()
+	}()
+}
+
 // cleanupOrphanedAnchorTabs closes any internal tabs (about:blank, test pages) that aren't our internal anchor.
 // This cleans up tabs left over from previous browser_tail runs.
 func (m *Manager) cleanupOrphanedAnchorTabs(tabs []*Tab) {
@@ -290,9 +342,10 @@ func (m *Manager) handleNewTarget(ctx context.Context, info *target.Info) {
 		info.Title,
 		info.URL,
 		m.tabRegistry.GetSessionID(),
-		m.fileManager,
+		m.sink,
 		m.config,
 	)
+	mon.SetSourceMaps(m.sourceMaps)
 
 	m.tabMonitors[targetID] = mon
 
@@ -306,9 +359,51 @@ func (m *Manager) handleNewTarget(ctx context.Context, info *target.Info) {
 	slog.Info("Started monitoring tab", "tab", tabID, "target_id", targetID[:8], "url", info.URL)
 }
 
-// handleTargetDestroyed handles a tab being closed.
+// handleNewWorker starts monitoring a service or shared worker.
+func (m *Manager) handleNewWorker(ctx context.Context, info *target.Info) {
+	targetID := string(info.TargetID)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.workerMonitors[targetID]; exists {
+		return
+	}
+
+	mon := monitor.NewWorkerMonitor(
+		ctx,
+		targetID,
+		info.Type,
+		info.URL,
+		m.tabRegistry.GetSessionID(),
+		m.sink,
+		m.config,
+	)
+	mon.SetSourceMaps(m.sourceMaps)
+
+	m.workerMonitors[targetID] = mon
+
+	go func() {
+		if err := mon.Start(m.browserCtx); err != nil {
+			slog.Error("Worker monitor error", "worker", targetID[:8], "error", err)
+		}
+	}()
+
+	slog.Info("Started monitoring worker", "type", info.Type, "target_id", targetID[:8], "url", info.URL)
+}
+
+// handleTargetDestroyed handles a tab or worker being closed.
 func (m *Manager) handleTargetDestroyed(targetID string) {
 	m.mu.Lock()
+	if worker, exists := m.workerMonitors[targetID]; exists {
+		delete(m.workerMonitors, targetID)
+		m.mu.Unlock()
+
+		m.stopMonitor(worker)
+		slog.Info("Worker stopped", "target_id", targetID[:8])
+		return
+	}
+
 	mon, exists := m.tabMonitors[targetID]
 	if !exists {
 		m.mu.Unlock()
@@ -319,7 +414,7 @@ func (m *Manager) handleTargetDestroyed(targetID string) {
 	m.mu.Unlock()
 
 	// Signal TabMonitor to shut down
-	mon.Stop()
+	m.stopMonitor(mon)
 
 	slog.Info("Tab closed", "tab", mon.TabID())
 }
@@ -347,6 +442,11 @@ func (m *Manager) handleTargetInfoChanged(info *target.Info) {
 func (m *Manager) Stop() {
 	slog.Info("Shutting down...")
 
+	// Hand downloads back to Chrome while the connection is still open
+	if m.downloadsEnabled {
+		m.resetDownloads(m.browserCtx)
+	}
+
 	// Cancel browser context to stop event listening
 	if m.browserCancel != nil {
 		m.browserCancel()
@@ -357,22 +457,13 @@ func (m *Manager) Stop() {
 		m.allocatorCancel()
 	}
 
-	// Stop all tab monitors
-	m.mu.Lock()
-	monitors := make([]*monitor.TabMonitor, 0, len(m.tabMonitors))
-	for _, mon := range m.tabMonitors {
-		monitors = append(monitors, mon)
-	}
-	m.tabMonitors = make(map[string]*monitor.TabMonitor)
-	m.mu.Unlock()
-
-	for _, mon := range monitors {
-		mon.Stop()
-	}
+	// Stop all tab and worker monitors and let them finish writing
+	m.clearTabMonitors()
+	m.stopping.Wait()
 
-	// Close all log files
-	if err := m.fileManager.Close(); err != nil {
-		slog.Error("Error closing log files", "error", err)
+	// Close all outputs (log files and any other sinks)
+	if err := m.sink.Close(); err != nil {
+		slog.Error("Error closing outputs", "error", err)
 	}
 
 	// Stop Chrome if we launched it
@@ -399,6 +490,13 @@ func (m *Manager) IsConnected() bool {
 	return m.connected
 }
 
+// isSharedWorkerType reports whether a target type is a worker that is not
+// owned by a single tab.
+func isSharedWorkerType(targetType string) bool {
+	return targetType == monitor.TargetTypeServiceWorker ||
+		targetType == monitor.TargetTypeSharedWorker
+}
+
 // isInternalURL checks if a URL belongs to an internal browser_tail tab.
 // This includes about:blank, data: URLs (test page), and URLs containing browser_tail.
 func isInternalURL(url string) bool {
@@ -12,16 +12,19 @@ import (
 	"testing"
 	"time"
 
+	"github.com/chromedp/cdproto/browser"
+
 	"github.com/ajsharma/browser_tail/internal/config"
 	"github.com/ajsharma/browser_tail/internal/logger"
+	"github.com/ajsharma/browser_tail/internal/monitor"
 )
 
 func TestNewManager(t *testing.T) {
 	cfg := &config.Config{
 		ChromePort: "9222",
-		OutputDir:  "./logs",
+		OutputDir:  t.TempDir(),
 	}
-	fm := logger.NewFileManager("./logs")
+	fm := logger.NewFileManager(cfg.OutputDir)
 
 	m := NewManager(cfg, fm)
 
@@ -31,12 +34,15 @@ func TestNewManager(t *testing.T) {
 	if m.config != cfg {
 		t.Error("config not set correctly")
 	}
-	if m.fileManager != fm {
-		t.Error("fileManager not set correctly")
+	if m.sink != fm {
+		t.Error("sink not set correctly")
 	}
 	if m.tabMonitors == nil {
 		t.Error("tabMonitors map not initialized")
 	}
+	if m.workerMonitors == nil {
+		t.Error("workerMonitors map not initialized")
+	}
 	if m.tabRegistry == nil {
 		t.Error("tabRegistry not initialized")
 	}
@@ -45,9 +51,9 @@ func TestNewManager(t *testing.T) {
 func TestManagerGetActiveTabCount(t *testing.T) {
 	cfg := &config.Config{
 		ChromePort: "9222",
-		OutputDir:  "./logs",
+		OutputDir:  t.TempDir(),
 	}
-	fm := logger.NewFileManager("./logs")
+	fm := logger.NewFileManager(cfg.OutputDir)
 	m := NewManager(cfg, fm)
 
 	// Initially should be 0
@@ -59,9 +65,9 @@ func TestManagerGetActiveTabCount(t *testing.T) {
 func TestManagerIsConnected(t *testing.T) {
 	cfg := &config.Config{
 		ChromePort: "9222",
-		OutputDir:  "./logs",
+		OutputDir:  t.TempDir(),
 	}
-	fm := logger.NewFileManager("./logs")
+	fm := logger.NewFileManager(cfg.OutputDir)
 	m := NewManager(cfg, fm)
 
 	// Initially should be false
@@ -73,9 +79,9 @@ func TestManagerIsConnected(t *testing.T) {
 func TestManagerConcurrentAccess(t *testing.T) {
 	cfg := &config.Config{
 		ChromePort: "9222",
-		OutputDir:  "./logs",
+		OutputDir:  t.TempDir(),
 	}
-	fm := logger.NewFileManager("./logs")
+	fm := logger.NewFileManager(cfg.OutputDir)
 	m := NewManager(cfg, fm)
 
 	// Test concurrent access to GetActiveTabCount and IsConnected
@@ -167,9 +173,9 @@ func TestCleanupOrphanedAnchorTabsFiltering(t *testing.T) {
 
 			cfg := &config.Config{
 				ChromePort: port,
-				OutputDir:  "./logs",
+				OutputDir:  t.TempDir(),
 			}
-			fm := logger.NewFileManager("./logs")
+			fm := logger.NewFileManager(cfg.OutputDir)
 			m := NewManager(cfg, fm)
 			m.internalTargetID = tt.internalTargetID
 
@@ -199,9 +205,9 @@ func TestCleanupOrphanedAnchorTabsFiltering(t *testing.T) {
 func TestManagerStopWithoutStart(t *testing.T) {
 	cfg := &config.Config{
 		ChromePort: "9222",
-		OutputDir:  "./logs",
+		OutputDir:  t.TempDir(),
 	}
-	fm := logger.NewFileManager("./logs")
+	fm := logger.NewFileManager(cfg.OutputDir)
 	m := NewManager(cfg, fm)
 
 	// Stop should not panic even when never started
@@ -211,9 +217,9 @@ func TestManagerStopWithoutStart(t *testing.T) {
 func TestClearTabMonitors(t *testing.T) {
 	cfg := &config.Config{
 		ChromePort: "9222",
-		OutputDir:  "./logs",
+		OutputDir:  t.TempDir(),
 	}
-	fm := logger.NewFileManager("./logs")
+	fm := logger.NewFileManager(cfg.OutputDir)
 	m := NewManager(cfg, fm)
 
 	// clearTabMonitors should work on empty map
@@ -224,13 +230,42 @@ func TestClearTabMonitors(t *testing.T) {
 	}
 }
 
+func TestHandleDownloadEventRouting(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.OutputDir = dir
+	fm := logger.NewFileManager(dir)
+	defer fm.Close()
+	m := NewManager(cfg, fm)
+
+	mon := monitor.NewTabMonitor(context.Background(), "target-1", "tab-1", "example.com", "", "https://example.com", "", fm, cfg)
+	defer mon.Stop()
+	m.tabMonitors["target-1"] = mon
+
+	// Downloads from unknown frames are ignored
+	m.handleDownloadEvent(&browser.EventDownloadWillBegin{FrameID: "other", GUID: "guid-0"})
+	if len(m.downloads) != 0 {
+		t.Errorf("expected download from unknown frame to be ignored, got %d tracked", len(m.downloads))
+	}
+
+	m.handleDownloadEvent(&browser.EventDownloadWillBegin{FrameID: "target-1", GUID: "guid-1", URL: "https://example.com/a.csv"})
+	if m.downloads["guid-1"] != mon {
+		t.Fatal("expected download to be routed to the tab that started it")
+	}
+
+	m.handleDownloadEvent(&browser.EventDownloadProgress{GUID: "guid-1", State: browser.DownloadProgressStateCanceled})
+	if len(m.downloads) != 0 {
+		t.Errorf("expected finished download to be forgotten, got %d tracked", len(m.downloads))
+	}
+}
+
 func TestManagerContextCancellation(t *testing.T) {
 	cfg := &config.Config{
 		ChromePort: "59999", // Port nothing is listening on
-		OutputDir:  "./logs",
+		OutputDir:  t.TempDir(),
 		AutoLaunch: false,
 	}
-	fm := logger.NewFileManager("./logs")
+	fm := logger.NewFileManager(cfg.OutputDir)
 	m := NewManager(cfg, fm)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -258,6 +293,27 @@ func TestManagerContextCancellation(t *testing.T) {
 	}
 }
 
+func TestIsSharedWorkerType(t *testing.T) {
+	tests := []struct {
+		targetType string
+		want       bool
+	}{
+		{"service_worker", true},
+		{"shared_worker", true},
+		{"worker", false},
+		{"page", false},
+		{"iframe", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.targetType, func(t *testing.T) {
+			if got := isSharedWorkerType(tt.targetType); got != tt.want {
+				t.Errorf("isSharedWorkerType(%q) = %v, want %v", tt.targetType, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestReconnectIntervalConstants(t *testing.T) {
 	if reconnectInterval <= 0 {
 		t.Error("reconnectInterval should be positive")
@@ -7,12 +7,33 @@ import (
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/ajsharma/browser_tail/internal/logger"
 )
 
 // Version is the current version of browser_tail.
 // This is set at build time via ldflags.
 var Version = "dev"
 
+// Dialog policies for JavaScript dialogs (alert, confirm, prompt, beforeunload).
+const (
+	DialogPolicyManual  = "manual"
+	DialogPolicyAccept  = "accept"
+	DialogPolicyDismiss = "dismiss"
+)
+
+// Naming schemes for rotated log files.
+const (
+	RotateNamingNumbered  = "numbered"
+	RotateNamingTimestamp = "timestamp"
+)
+
+// Output sinks events can be sent to.
+const (
+	SinkFile   = "file"
+	SinkStdout = "stdout"
+)
+
 // Config holds all configuration options for browser_tail.
 type Config struct {
 	// Connection
@@ -24,6 +45,37 @@ type Config struct {
 	FlushInterval time.Duration `yaml:"flush_interval"`
 	BufferSize    int           `yaml:"buffer_size"`
 
+	// Sinks lists where events are sent; every event goes to each of them.
+	// "file" writes JSONL logs under OutputDir; "stdout" streams every event
+	// as one JSON line to standard output.
+	Sinks []string `yaml:"sinks"`
+
+	// Log Rotation
+	// RotateMaxSizeMB and RotateMaxAge roll session.log over once it reaches
+	// that size or has been open that long; zero disables each. RotateOnStart
+	// rolls over a file left by a previous run. RotateNaming is "numbered"
+	// (session.<n>.log) or "timestamp" (session.<UTC time>.log).
+	RotateMaxSizeMB int           `yaml:"rotate_max_size_mb"`
+	RotateMaxAge    time.Duration `yaml:"rotate_max_age"`
+	RotateOnStart   bool          `yaml:"rotate_on_start"`
+	RotateNaming    string        `yaml:"rotate_naming"`
+
+	// Compression compresses log files with "gzip" or "zstd" once they are
+	// closed or rotated ("none" to disable). CompressStream writes them
+	// compressed from the start instead.
+	Compression    string `yaml:"compression"`
+	CompressStream bool   `yaml:"compress_stream"`
+
+	// Retention
+	// Old logs under OutputDir are removed every RetentionInterval: files
+	// older than RetentionMaxAge, each site's oldest files beyond
+	// RetentionMaxFilesPerSite, then the oldest files until the total is
+	// under RetentionMaxSizeMB. Zero disables each limit.
+	RetentionMaxAge          time.Duration `yaml:"retention_max_age"`
+	RetentionMaxSizeMB       int           `yaml:"retention_max_size_mb"`
+	RetentionMaxFilesPerSite int           `yaml:"retention_max_files_per_site"`
+	RetentionInterval        time.Duration `yaml:"retention_interval"`
+
 	// Privacy & Body Capture
 	Redact           bool     `yaml:"redact"`
 	CaptureBodies    bool     `yaml:"capture_bodies"`
@@ -35,6 +87,62 @@ type Config struct {
 	EnableConsole bool `yaml:"enable_console"`
 	EnableErrors  bool `yaml:"enable_errors"`
 	EnablePage    bool `yaml:"enable_page"`
+
+	// EnableWebSocket logs WebSocket lifecycle and frames (requires EnableNetwork).
+	EnableWebSocket bool `yaml:"enable_websocket"`
+
+	// EnableWorkers monitors dedicated, shared and service workers.
+	EnableWorkers bool `yaml:"enable_workers"`
+
+	// EnableFrames monitors out-of-process (cross-origin) iframes.
+	EnableFrames bool `yaml:"enable_frames"`
+
+	// EnableBrowserLog logs browser-generated messages (CSP, interventions,
+	// violations, deprecations) from the Log domain.
+	EnableBrowserLog bool `yaml:"enable_browser_log"`
+
+	// EnableAudits logs inspector issues (cookies, CORS, mixed content, ...)
+	// from the Audits domain.
+	EnableAudits bool `yaml:"enable_audits"`
+
+	// EnableWebVitals injects a PerformanceObserver script into pages to
+	// report Core Web Vitals (LCP, CLS, INP, FCP, TTFB).
+	EnableWebVitals bool `yaml:"enable_web_vitals"`
+
+	// EnableLongTasks injects a PerformanceObserver script into pages to
+	// report main-thread tasks longer than 50ms.
+	EnableLongTasks bool `yaml:"enable_long_tasks"`
+
+	// EnableStorage logs cookie, localStorage, sessionStorage, IndexedDB and
+	// Cache Storage writes, plus a snapshot when a tab first shows an origin.
+	EnableStorage bool `yaml:"enable_storage"`
+
+	// EnableSecurity logs each origin's TLS certificate on first contact and
+	// changes to the page's security state (requires EnableNetwork for
+	// certificates).
+	EnableSecurity bool `yaml:"enable_security"`
+
+	// PerfMetricsInterval is how often each tab's Performance.getMetrics
+	// is sampled into perf.metrics events. Zero disables sampling.
+	PerfMetricsInterval time.Duration `yaml:"perf_metrics_interval"`
+
+	// DialogPolicy answers JavaScript dialogs automatically so unattended
+	// sessions don't hang: "accept", "dismiss", or "manual" to leave them
+	// for the user.
+	DialogPolicy string `yaml:"dialog_policy"`
+
+	// EnableDownloads logs downloads started from monitored tabs.
+	// SaveDownloads also saves the files under <output_dir>/_downloads/<session>
+	// instead of Chrome's download location.
+	EnableDownloads bool `yaml:"enable_downloads"`
+	SaveDownloads   bool `yaml:"save_downloads"`
+
+	// Source Maps
+	// EnableSourceMaps fetches maps referenced by page scripts to symbolicate
+	// stack frames. SourceMapDir is searched for "<script>.map" files first,
+	// and is used even when fetching is disabled.
+	EnableSourceMaps bool   `yaml:"enable_source_maps"`
+	SourceMapDir     string `yaml:"source_map_dir"`
 }
 
 // DefaultConfig returns the default configuration.
@@ -48,6 +156,23 @@ func DefaultConfig() *Config {
 		OutputDir:     "./logs",
 		FlushInterval: 100 * time.Millisecond,
 		BufferSize:    8 * 1024, // 8 KB
+		Sinks:         []string{SinkFile},
+
+		// Log Rotation
+		RotateMaxSizeMB: 0,
+		RotateMaxAge:    0,
+		RotateOnStart:   false,
+		RotateNaming:    RotateNamingNumbered,
+
+		// Compression
+		Compression:    logger.CompressionNone,
+		CompressStream: false,
+
+		// Retention
+		RetentionMaxAge:          0,
+		RetentionMaxSizeMB:       0,
+		RetentionMaxFilesPerSite: 0,
+		RetentionInterval:        10 * time.Minute,
 
 		// Privacy & Body Capture
 		Redact:           true,
@@ -60,6 +185,28 @@ func DefaultConfig() *Config {
 		EnableConsole: true,
 		EnableErrors:  true,
 		EnablePage:    true,
+
+		EnableWebSocket: true,
+		EnableWorkers:   false,
+		EnableFrames:    false,
+
+		EnableBrowserLog: false,
+		EnableAudits:     false,
+		EnableWebVitals:  false,
+		EnableLongTasks:  false,
+		EnableStorage:    false,
+		EnableSecurity:   false,
+
+		PerfMetricsInterval: 0,
+
+		DialogPolicy: DialogPolicyManual,
+
+		EnableDownloads: false,
+		SaveDownloads:   false,
+
+		// Source Maps
+		EnableSourceMaps: false,
+		SourceMapDir:     "",
 	}
 }
 
@@ -91,8 +238,60 @@ func (c *Config) Validate() error {
 	if c.BufferSize < 1024 {
 		return fmt.Errorf("buffer_size must be at least 1024 bytes")
 	}
+	if len(c.Sinks) == 0 {
+		return fmt.Errorf("sinks must list at least one output")
+	}
+	seen := make(map[string]bool, len(c.Sinks))
+	for _, sink := range c.Sinks {
+		switch sink {
+		case SinkFile, SinkStdout:
+		default:
+			return fmt.Errorf("unknown sink %q", sink)
+		}
+		if seen[sink] {
+			return fmt.Errorf("sink %q is listed more than once", sink)
+		}
+		seen[sink] = true
+	}
+	if c.RotateMaxSizeMB < 0 {
+		return fmt.Errorf("rotate_max_size_mb must be 0 (disabled) or positive")
+	}
+	if c.RotateMaxAge != 0 && c.RotateMaxAge < time.Minute {
+		return fmt.Errorf("rotate_max_age must be 0 (disabled) or at least 1m")
+	}
+	switch c.RotateNaming {
+	case "", RotateNamingNumbered, RotateNamingTimestamp:
+	default:
+		return fmt.Errorf("rotate_naming must be numbered or timestamp, got %q", c.RotateNaming)
+	}
+	switch c.Compression {
+	case "", logger.CompressionNone, logger.CompressionGzip, logger.CompressionZstd:
+	default:
+		return fmt.Errorf("compression must be one of none, gzip or zstd, got %q", c.Compression)
+	}
+	if c.CompressStream && (c.Compression == "" || c.Compression == logger.CompressionNone) {
+		return fmt.Errorf("compress_stream requires compression to be gzip or zstd")
+	}
+	if c.SaveDownloads && !c.EnableDownloads {
+		return fmt.Errorf("save_downloads requires enable_downloads")
+	}
+	if c.RetentionMaxAge < 0 || c.RetentionMaxSizeMB < 0 || c.RetentionMaxFilesPerSite < 0 {
+		return fmt.Errorf("retention limits must be 0 (disabled) or positive")
+	}
+	retentionEnabled := c.RetentionMaxAge > 0 || c.RetentionMaxSizeMB > 0 || c.RetentionMaxFilesPerSite > 0
+	if retentionEnabled && c.RetentionInterval < time.Minute {
+		return fmt.Errorf("retention_interval must be at least 1m")
+	}
 	if c.BodySizeLimitKB < 1 {
 		return fmt.Errorf("body_size_limit_kb must be at least 1")
 	}
+	if c.PerfMetricsInterval != 0 && c.PerfMetricsInterval < time.Second {
+		return fmt.Errorf("perf_metrics_interval must be 0 (disabled) or at least 1s")
+	}
+	switch c.DialogPolicy {
+	case "", DialogPolicyManual, DialogPolicyAccept, DialogPolicyDismiss:
+	default:
+		return fmt.Errorf("dialog_policy must be one of manual, accept or dismiss, got %q", c.DialogPolicy)
+	}
 	return nil
 }
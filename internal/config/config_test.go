@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/ajsharma/browser_tail/internal/logger"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -30,6 +32,46 @@ func TestDefaultConfig(t *testing.T) {
 		t.Errorf("expected BufferSize 8192, got %d", cfg.BufferSize)
 	}
 
+	if len(cfg.Sinks) != 1 || cfg.Sinks[0] != SinkFile {
+		t.Errorf("expected Sinks [file], got %v", cfg.Sinks)
+	}
+
+	// Rotation defaults
+	if cfg.RotateMaxSizeMB != 0 {
+		t.Errorf("expected RotateMaxSizeMB 0, got %d", cfg.RotateMaxSizeMB)
+	}
+	if cfg.RotateMaxAge != 0 {
+		t.Errorf("expected RotateMaxAge 0, got %v", cfg.RotateMaxAge)
+	}
+	if cfg.RotateOnStart != false {
+		t.Errorf("expected RotateOnStart false, got %v", cfg.RotateOnStart)
+	}
+	if cfg.RotateNaming != RotateNamingNumbered {
+		t.Errorf("expected RotateNaming numbered, got %s", cfg.RotateNaming)
+	}
+
+	// Compression defaults
+	if cfg.Compression != logger.CompressionNone {
+		t.Errorf("expected Compression none, got %s", cfg.Compression)
+	}
+	if cfg.CompressStream != false {
+		t.Errorf("expected CompressStream false, got %v", cfg.CompressStream)
+	}
+
+	// Retention defaults
+	if cfg.RetentionMaxAge != 0 {
+		t.Errorf("expected RetentionMaxAge 0, got %v", cfg.RetentionMaxAge)
+	}
+	if cfg.RetentionMaxSizeMB != 0 {
+		t.Errorf("expected RetentionMaxSizeMB 0, got %d", cfg.RetentionMaxSizeMB)
+	}
+	if cfg.RetentionMaxFilesPerSite != 0 {
+		t.Errorf("expected RetentionMaxFilesPerSite 0, got %d", cfg.RetentionMaxFilesPerSite)
+	}
+	if cfg.RetentionInterval != 10*time.Minute {
+		t.Errorf("expected RetentionInterval 10m, got %v", cfg.RetentionInterval)
+	}
+
 	// Privacy defaults
 	if cfg.Redact != true {
 		t.Errorf("expected Redact true, got %v", cfg.Redact)
@@ -54,6 +96,48 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.EnablePage != true {
 		t.Errorf("expected EnablePage true, got %v", cfg.EnablePage)
 	}
+	if cfg.EnableWebSocket != true {
+		t.Errorf("expected EnableWebSocket true, got %v", cfg.EnableWebSocket)
+	}
+	if cfg.EnableWorkers != false {
+		t.Errorf("expected EnableWorkers false, got %v", cfg.EnableWorkers)
+	}
+	if cfg.EnableFrames != false {
+		t.Errorf("expected EnableFrames false, got %v", cfg.EnableFrames)
+	}
+	if cfg.EnableBrowserLog != false {
+		t.Errorf("expected EnableBrowserLog false, got %v", cfg.EnableBrowserLog)
+	}
+	if cfg.EnableAudits != false {
+		t.Errorf("expected EnableAudits false, got %v", cfg.EnableAudits)
+	}
+	if cfg.EnableWebVitals != false {
+		t.Errorf("expected EnableWebVitals false, got %v", cfg.EnableWebVitals)
+	}
+	if cfg.EnableLongTasks != false {
+		t.Errorf("expected EnableLongTasks false, got %v", cfg.EnableLongTasks)
+	}
+	if cfg.EnableStorage != false {
+		t.Errorf("expected EnableStorage false, got %v", cfg.EnableStorage)
+	}
+	if cfg.EnableSecurity != false {
+		t.Errorf("expected EnableSecurity false, got %v", cfg.EnableSecurity)
+	}
+	if cfg.PerfMetricsInterval != 0 {
+		t.Errorf("expected PerfMetricsInterval 0, got %v", cfg.PerfMetricsInterval)
+	}
+	if cfg.DialogPolicy != DialogPolicyManual {
+		t.Errorf("expected DialogPolicy manual, got %s", cfg.DialogPolicy)
+	}
+	if cfg.EnableDownloads != false {
+		t.Errorf("expected EnableDownloads false, got %v", cfg.EnableDownloads)
+	}
+	if cfg.SaveDownloads != false {
+		t.Errorf("expected SaveDownloads false, got %v", cfg.SaveDownloads)
+	}
+	if cfg.EnableSourceMaps != false {
+		t.Errorf("expected EnableSourceMaps false, got %v", cfg.EnableSourceMaps)
+	}
 }
 
 func TestLoadFromFile(t *testing.T) {
@@ -74,6 +158,7 @@ enable_network: true
 enable_console: false
 enable_errors: true
 enable_page: false
+enable_websocket: false
 `
 
 	err := os.WriteFile(configPath, []byte(configContent), 0o644)
@@ -117,6 +202,9 @@ enable_page: false
 	if cfg.EnablePage != false {
 		t.Errorf("expected EnablePage false, got %v", cfg.EnablePage)
 	}
+	if cfg.EnableWebSocket != false {
+		t.Errorf("expected EnableWebSocket false, got %v", cfg.EnableWebSocket)
+	}
 }
 
 func TestLoadFromFileNotFound(t *testing.T) {
@@ -209,6 +297,112 @@ func TestValidate(t *testing.T) {
 			modify:  func(c *Config) { c.BodySizeLimitKB = 0 },
 			wantErr: true,
 		},
+		{
+			name:    "perf metrics disabled",
+			modify:  func(c *Config) { c.PerfMetricsInterval = 0 },
+			wantErr: false,
+		},
+		{
+			name:    "perf metrics interval too short",
+			modify:  func(c *Config) { c.PerfMetricsInterval = 100 * time.Millisecond },
+			wantErr: true,
+		},
+		{
+			name:    "file and stdout sinks",
+			modify:  func(c *Config) { c.Sinks = []string{SinkFile, SinkStdout} },
+			wantErr: false,
+		},
+		{
+			name:    "no sinks",
+			modify:  func(c *Config) { c.Sinks = nil },
+			wantErr: true,
+		},
+		{
+			name:    "unknown sink",
+			modify:  func(c *Config) { c.Sinks = []string{"kafka"} },
+			wantErr: true,
+		},
+		{
+			name:    "duplicate sink",
+			modify:  func(c *Config) { c.Sinks = []string{SinkFile, SinkFile} },
+			wantErr: true,
+		},
+		{
+			name:    "negative rotate size",
+			modify:  func(c *Config) { c.RotateMaxSizeMB = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "rotate age too short",
+			modify:  func(c *Config) { c.RotateMaxAge = time.Second },
+			wantErr: true,
+		},
+		{
+			name:    "rotate naming timestamp",
+			modify:  func(c *Config) { c.RotateNaming = RotateNamingTimestamp },
+			wantErr: false,
+		},
+		{
+			name:    "unknown rotate naming",
+			modify:  func(c *Config) { c.RotateNaming = "dated" },
+			wantErr: true,
+		},
+		{
+			name:    "compression zstd",
+			modify:  func(c *Config) { c.Compression = logger.CompressionZstd },
+			wantErr: false,
+		},
+		{
+			name:    "unknown compression",
+			modify:  func(c *Config) { c.Compression = "bzip2" },
+			wantErr: true,
+		},
+		{
+			name:    "compress stream without compression",
+			modify:  func(c *Config) { c.CompressStream = true },
+			wantErr: true,
+		},
+		{
+			name:    "save downloads without download events",
+			modify:  func(c *Config) { c.SaveDownloads = true },
+			wantErr: true,
+		},
+		{
+			name: "save downloads with download events",
+			modify: func(c *Config) {
+				c.EnableDownloads = true
+				c.SaveDownloads = true
+			},
+			wantErr: false,
+		},
+		{
+			name:    "negative retention files",
+			modify:  func(c *Config) { c.RetentionMaxFilesPerSite = -1 },
+			wantErr: true,
+		},
+		{
+			name: "retention interval too short",
+			modify: func(c *Config) {
+				c.RetentionMaxAge = time.Hour
+				c.RetentionInterval = time.Second
+			},
+			wantErr: true,
+		},
+		{
+			name:    "retention interval ignored without limits",
+			modify:  func(c *Config) { c.RetentionInterval = 0 },
+			wantErr: false,
+		},
+		{
+			name:    "dialog policy accept",
+			modify:  func(c *Config) { c.DialogPolicy = DialogPolicyAccept },
+			wantErr: false,
+		},
+		{
+			name:    "unknown dialog policy",
+			modify:  func(c *Config) { c.DialogPolicy = "ignore" },
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -7,11 +7,15 @@ import (
 
 // LogEvent represents a single logged event in JSONL format.
 type LogEvent struct {
-	Timestamp string      `json:"timestamp"`
-	Site      string      `json:"site"`
-	TabID     string      `json:"tab_id"`
-	EventType string      `json:"event_type"`
-	Data      interface{} `json:"data"`
+	Timestamp  string      `json:"timestamp"`
+	Site       string      `json:"site"`
+	TabID      string      `json:"tab_id"`
+	WorkerID   string      `json:"worker_id,omitempty"`
+	WorkerType string      `json:"worker_type,omitempty"`
+	FrameID    string      `json:"frame_id,omitempty"`
+	FrameURL   string      `json:"frame_url,omitempty"`
+	EventType  string      `json:"event_type"`
+	Data       interface{} `json:"data"`
 }
 
 // NewLogEvent creates a new LogEvent with the current timestamp.
@@ -33,6 +37,12 @@ const (
 	EventMetaSiteChanged  = "meta.site_changed"
 	EventMetaSiteEntered  = "meta.site_entered"
 	EventMetaEnvironment  = "meta.environment"
+
+	EventMetaWorkerAttached = "meta.worker_attached"
+	EventMetaWorkerDetached = "meta.worker_detached"
+
+	EventMetaLogRotated      = "meta.log_rotated"
+	EventMetaRetentionPruned = "meta.retention_pruned"
 )
 
 // Event type constants for page events.
@@ -43,14 +53,38 @@ const (
 	EventPageClose    = "page.close"
 	EventPageLoad     = "page.load"
 	EventPageDOMReady = "page.dom_ready"
+
+	EventPageFrameAttached  = "page.frame_attached"
+	EventPageFrameDetached  = "page.frame_detached"
+	EventPageFrameNavigated = "page.frame_navigated"
+
+	EventPageDialogOpened = "page.dialog_opened"
+	EventPageDialogClosed = "page.dialog_closed"
+
+	EventPageDownloadStarted   = "page.download_started"
+	EventPageDownloadProgress  = "page.download_progress"
+	EventPageDownloadCompleted = "page.download_completed"
 )
 
 // Event type constants for network events.
 const (
 	EventNetworkRequest      = "network.request"
+	EventNetworkRequestBody  = "network.request_body"
 	EventNetworkResponse     = "network.response"
 	EventNetworkResponseBody = "network.response_body"
+	EventNetworkFinished     = "network.finished"
+	EventNetworkRedirect     = "network.redirect"
 	EventNetworkFailure      = "network.failure"
+	EventNetworkSSEMessage   = "network.sse_message"
+)
+
+// Event type constants for WebSocket events.
+const (
+	EventNetworkWSOpen          = "network.ws_open"
+	EventNetworkWSFrameSent     = "network.ws_frame_sent"
+	EventNetworkWSFrameReceived = "network.ws_frame_received"
+	EventNetworkWSClose         = "network.ws_close"
+	EventNetworkWSError         = "network.ws_error"
 )
 
 // Event type constants for console events.
@@ -61,12 +95,62 @@ const (
 	EventConsoleError   = "console.error"
 	EventConsoleDebug   = "console.debug"
 	EventConsoleVerbose = "console.verbose"
+
+	EventConsoleTable          = "console.table"
+	EventConsoleDir            = "console.dir"
+	EventConsoleDirXML         = "console.dirxml"
+	EventConsoleTrace          = "console.trace"
+	EventConsoleGroup          = "console.group"
+	EventConsoleGroupCollapsed = "console.group_collapsed"
+	EventConsoleGroupEnd       = "console.group_end"
+	EventConsoleAssert         = "console.assert"
+	EventConsoleCount          = "console.count"
+	EventConsoleTimeEnd        = "console.time_end"
+	EventConsoleClear          = "console.clear"
+	EventConsoleProfile        = "console.profile"
+	EventConsoleProfileEnd     = "console.profile_end"
+)
+
+// Event type constants for browser log events.
+const (
+	EventLogEntry = "log.entry"
+)
+
+// Event type constants for audit events.
+const (
+	EventAuditIssue = "audit.issue"
+)
+
+// Event type constants for storage events.
+const (
+	EventStorageSnapshot     = "storage.snapshot"
+	EventStorageCookieSet    = "storage.cookie_set"
+	EventStorageItemAdded    = "storage.item_added"
+	EventStorageItemUpdated  = "storage.item_updated"
+	EventStorageItemRemoved  = "storage.item_removed"
+	EventStorageCleared      = "storage.cleared"
+	EventStorageIndexedDB    = "storage.indexeddb_changed"
+	EventStorageCacheStorage = "storage.cache_changed"
+)
+
+// Event type constants for security events.
+const (
+	EventSecurityCertificate  = "security.certificate"
+	EventSecurityStateChanged = "security.state_changed"
+)
+
+// Event type constants for performance events.
+const (
+	EventPerfWebVitals = "perf.web_vitals"
+	EventPerfLongTask  = "perf.long_task"
+	EventPerfMetrics   = "perf.metrics"
 )
 
 // Event type constants for error events.
 const (
 	EventErrorRuntime          = "error.runtime"
 	EventErrorUnhandledPromise = "error.unhandled_promise"
+	EventErrorPromiseHandled   = "error.promise_handled"
 )
 
 // SessionStartData holds data for meta.session_start events.
@@ -105,6 +189,46 @@ type SiteEnteredData struct {
 	URL      string `json:"url"`
 }
 
+// WorkerAttachedData holds data for meta.worker_attached events.
+type WorkerAttachedData struct {
+	WorkerID   string `json:"worker_id"`
+	WorkerType string `json:"worker_type"`
+	URL        string `json:"url"`
+}
+
+// WorkerDetachedData holds data for meta.worker_detached events.
+type WorkerDetachedData struct {
+	WorkerID        string  `json:"worker_id"`
+	WorkerType      string  `json:"worker_type"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// LogRotatedData holds data for meta.log_rotated events. The same event is
+// written as the last line of the rotated file and the first line of the new
+// one. Reason is "size", "age" or "session_start"; RotatedFile is the name the
+// old file was renamed to.
+type LogRotatedData struct {
+	Reason      string `json:"reason"`
+	RotatedFile string `json:"rotated_file"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
+
+// RetentionPrunedData holds data for meta.retention_pruned events.
+type RetentionPrunedData struct {
+	Files      []PrunedFile `json:"files"`
+	BytesFreed int64        `json:"bytes_freed"`
+}
+
+// PrunedFile describes a log file removed by retention. Path is relative to
+// the output directory; Reason is "age", "site_files" or "total_size".
+type PrunedFile struct {
+	Path       string `json:"path"`
+	Site       string `json:"site"`
+	Reason     string `json:"reason"`
+	SizeBytes  int64  `json:"size_bytes"`
+	ModifiedAt string `json:"modified_at"`
+}
+
 // PageNavigateData holds data for page.navigate events.
 type PageNavigateData struct {
 	URL            string `json:"url"`
@@ -122,12 +246,204 @@ type PageDOMReadyData struct {
 	URL string `json:"url"`
 }
 
+// FrameAttachedData holds data for page.frame_attached events.
+type FrameAttachedData struct {
+	FrameID       string `json:"frame_id"`
+	ParentFrameID string `json:"parent_frame_id"`
+}
+
+// FrameDetachedData holds data for page.frame_detached events.
+type FrameDetachedData struct {
+	FrameID string `json:"frame_id"`
+	Reason  string `json:"reason"`
+}
+
+// FrameNavigatedData holds data for page.frame_navigated events.
+type FrameNavigatedData struct {
+	FrameID        string `json:"frame_id"`
+	ParentFrameID  string `json:"parent_frame_id,omitempty"`
+	Name           string `json:"name,omitempty"`
+	URL            string `json:"url"`
+	NavigationType string `json:"navigation_type,omitempty"`
+}
+
+// DialogOpenedData holds data for page.dialog_opened events.
+// Policy is set when the dialog will be answered automatically.
+type DialogOpenedData struct {
+	Type          string `json:"type"`
+	Message       string `json:"message"`
+	URL           string `json:"url,omitempty"`
+	FrameID       string `json:"frame_id,omitempty"`
+	DefaultPrompt string `json:"default_prompt,omitempty"`
+	Policy        string `json:"policy,omitempty"`
+}
+
+// DialogClosedData holds data for page.dialog_closed events.
+type DialogClosedData struct {
+	Type       string  `json:"type,omitempty"`
+	FrameID    string  `json:"frame_id,omitempty"`
+	Accepted   bool    `json:"accepted"`
+	UserInput  string  `json:"user_input,omitempty"`
+	Handler    string  `json:"handler,omitempty"`
+	DurationMs float64 `json:"duration_ms,omitempty"`
+}
+
+// DownloadStartedData holds data for page.download_started events.
+type DownloadStartedData struct {
+	GUID              string `json:"guid"`
+	URL               string `json:"url"`
+	SuggestedFilename string `json:"suggested_filename"`
+	FrameID           string `json:"frame_id,omitempty"`
+}
+
+// DownloadProgressData holds data for page.download_progress events.
+type DownloadProgressData struct {
+	GUID          string  `json:"guid"`
+	ReceivedBytes float64 `json:"received_bytes"`
+	TotalBytes    float64 `json:"total_bytes"`
+}
+
+// DownloadCompletedData holds data for page.download_completed events.
+// State is "completed" or "canceled".
+type DownloadCompletedData struct {
+	GUID              string  `json:"guid"`
+	URL               string  `json:"url,omitempty"`
+	SuggestedFilename string  `json:"suggested_filename,omitempty"`
+	State             string  `json:"state"`
+	ReceivedBytes     float64 `json:"received_bytes"`
+	TotalBytes        float64 `json:"total_bytes"`
+	FilePath          string  `json:"file_path,omitempty"`
+	DurationMs        float64 `json:"duration_ms"`
+}
+
+// StorageCookie describes a cookie in storage events. Expires is empty for
+// session cookies.
+type StorageCookie struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Domain      string `json:"domain,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Expires     string `json:"expires,omitempty"`
+	MaxAge      int    `json:"max_age,omitempty"`
+	Secure      bool   `json:"secure,omitempty"`
+	HTTPOnly    bool   `json:"http_only,omitempty"`
+	SameSite    string `json:"same_site,omitempty"`
+	Partitioned bool   `json:"partitioned,omitempty"`
+}
+
+// CookieSetData holds data for storage.cookie_set events, one per Set-Cookie
+// line in a response. Blocked cookies were not stored by the browser.
+type CookieSetData struct {
+	RequestID      string   `json:"request_id"`
+	URL            string   `json:"url,omitempty"`
+	RawCookie      string   `json:"raw_cookie,omitempty"` // set if the line could not be parsed
+	Blocked        bool     `json:"blocked,omitempty"`
+	BlockedReasons []string `json:"blocked_reasons,omitempty"`
+	StorageCookie
+}
+
+// StorageItemData holds data for storage.item_added, storage.item_updated
+// and storage.item_removed events. StorageType is "local" or "session".
+type StorageItemData struct {
+	StorageType string `json:"storage_type"`
+	Origin      string `json:"origin"`
+	Key         string `json:"key"`
+	Value       string `json:"value,omitempty"`
+	OldValue    string `json:"old_value,omitempty"`
+}
+
+// StorageClearedData holds data for storage.cleared events.
+type StorageClearedData struct {
+	StorageType string `json:"storage_type"`
+	Origin      string `json:"origin"`
+}
+
+// StorageIndexedDBData holds data for storage.indexeddb_changed events.
+// Database is empty when the list of databases changed.
+type StorageIndexedDBData struct {
+	Origin      string `json:"origin"`
+	Database    string `json:"database,omitempty"`
+	ObjectStore string `json:"object_store,omitempty"`
+}
+
+// StorageCacheData holds data for storage.cache_changed events.
+// CacheName is empty when the list of caches changed.
+type StorageCacheData struct {
+	Origin    string `json:"origin"`
+	CacheName string `json:"cache_name,omitempty"`
+}
+
+// StorageSnapshotData holds data for storage.snapshot events, written when a
+// tab first shows an origin.
+type StorageSnapshotData struct {
+	Origin         string            `json:"origin"`
+	Cookies        []StorageCookie   `json:"cookies"`
+	LocalStorage   map[string]string `json:"local_storage"`
+	SessionStorage map[string]string `json:"session_storage"`
+}
+
+// CertificateData holds data for security.certificate events, written the
+// first time a tab connects to an origin over TLS. Times are RFC 3339.
+// ExpiresInDays counts whole days until ValidTo; Expired is set once it has passed.
+type CertificateData struct {
+	Origin               string   `json:"origin"`
+	RequestID            string   `json:"request_id"`
+	Protocol             string   `json:"protocol"`
+	KeyExchange          string   `json:"key_exchange,omitempty"`
+	Cipher               string   `json:"cipher"`
+	Subject              string   `json:"subject"`
+	Issuer               string   `json:"issuer"`
+	SANs                 []string `json:"sans,omitempty"`
+	ValidFrom            string   `json:"valid_from,omitempty"`
+	ValidTo              string   `json:"valid_to,omitempty"`
+	ExpiresInDays        int      `json:"expires_in_days"`
+	Expired              bool     `json:"expired,omitempty"`
+	CTCompliance         string   `json:"ct_compliance,omitempty"`
+	EncryptedClientHello bool     `json:"encrypted_client_hello,omitempty"`
+}
+
+// SecurityStateChangedData holds data for security.state_changed events.
+// Issues lists Chrome's security state issue IDs, such as mixed content.
+type SecurityStateChangedData struct {
+	SecurityState      string   `json:"security_state"`
+	PreviousState      string   `json:"previous_state,omitempty"`
+	Issues             []string `json:"issues,omitempty"`
+	CertificateError   string   `json:"certificate_error,omitempty"`
+	CertificateSubject string   `json:"certificate_subject,omitempty"`
+	CertificateValidTo string   `json:"certificate_valid_to,omitempty"`
+	ObsoleteTLS        bool     `json:"obsolete_tls,omitempty"`
+	WeakSignature      bool     `json:"weak_signature,omitempty"`
+}
+
 // NetworkRequestData holds data for network.request events.
 type NetworkRequestData struct {
+	RequestID string                 `json:"request_id"`
+	URL       string                 `json:"url"`
+	Method    string                 `json:"method"`
+	Type      string                 `json:"type"`
+	Headers   map[string]interface{} `json:"headers,omitempty"`
+	PostData  string                 `json:"post_data,omitempty"`
+	Initiator *NetworkInitiatorData  `json:"initiator,omitempty"`
+	Priority  string                 `json:"priority,omitempty"`
+}
+
+// NetworkInitiatorData describes what caused a network request to be sent.
+type NetworkInitiatorData struct {
+	Type      string  `json:"type"`
+	URL       string  `json:"url,omitempty"`
+	Line      float64 `json:"line,omitempty"`
+	Column    float64 `json:"column,omitempty"`
+	RequestID string  `json:"request_id,omitempty"`
+}
+
+// NetworkRequestBodyData holds data for network.request_body events.
+// It is emitted when post data was too large to be inlined in network.request
+// and had to be fetched separately.
+type NetworkRequestBodyData struct {
 	RequestID string `json:"request_id"`
 	URL       string `json:"url"`
-	Method    string `json:"method"`
-	Type      string `json:"type"`
+	MimeType  string `json:"mime_type"`
+	Body      string `json:"body"`
 }
 
 // NetworkResponseData holds data for network.response events.
@@ -139,6 +455,64 @@ type NetworkResponseData struct {
 	MimeType      string                 `json:"mime_type"`
 	Headers       map[string]interface{} `json:"headers"`
 	EncodedLength float64                `json:"encoded_length"`
+
+	// Connection and cache details
+	Protocol                    string  `json:"protocol"`
+	RemoteIP                    string  `json:"remote_ip"`
+	RemotePort                  int64   `json:"remote_port,omitempty"`
+	ConnectionReused            bool    `json:"connection_reused"`
+	ConnectionID                float64 `json:"connection_id"`
+	FromDiskCache               bool    `json:"from_disk_cache"`
+	FromPrefetchCache           bool    `json:"from_prefetch_cache,omitempty"`
+	FromServiceWorker           bool    `json:"from_service_worker"`
+	ServiceWorkerResponseSource string  `json:"service_worker_response_source,omitempty"`
+	SecurityState               string  `json:"security_state"`
+
+	Timing        *NetworkTimingData `json:"timing,omitempty"`
+	RedirectChain []RedirectHop      `json:"redirect_chain,omitempty"`
+}
+
+// RedirectHop is one redirect response in a request's redirect chain.
+type RedirectHop struct {
+	URL    string `json:"url"`
+	Status int64  `json:"status"`
+}
+
+// NetworkRedirectData holds data for network.redirect events.
+// Hop is the 1-based position of this redirect in the request's chain.
+type NetworkRedirectData struct {
+	RequestID string `json:"request_id"`
+	FromURL   string `json:"from_url"`
+	ToURL     string `json:"to_url"`
+	Status    int64  `json:"status"`
+	Location  string `json:"location"`
+	Hop       int    `json:"hop"`
+}
+
+// NetworkTimingData holds the phases of a request up to its response headers,
+// in milliseconds. Phases that did not occur (e.g. DNS and connect on a reused
+// connection) are omitted. HeadersMs is the time from the start of the request
+// until the response headers were received.
+type NetworkTimingData struct {
+	ProxyMs   float64 `json:"proxy_ms,omitempty"`
+	DNSMs     float64 `json:"dns_ms,omitempty"`
+	ConnectMs float64 `json:"connect_ms,omitempty"`
+	TLSMs     float64 `json:"tls_ms,omitempty"`
+	WorkerMs  float64 `json:"worker_ms,omitempty"`
+	SendMs    float64 `json:"send_ms,omitempty"`
+	WaitMs    float64 `json:"wait_ms,omitempty"`
+	HeadersMs float64 `json:"headers_ms"`
+}
+
+// NetworkFinishedData holds data for network.finished events.
+// DurationMs spans from the first request (including redirects) to the last
+// byte; ReceiveMs is the time spent downloading the body after the headers.
+type NetworkFinishedData struct {
+	RequestID         string  `json:"request_id"`
+	URL               string  `json:"url,omitempty"`
+	EncodedDataLength float64 `json:"encoded_data_length"`
+	DurationMs        float64 `json:"duration_ms,omitempty"`
+	ReceiveMs         float64 `json:"receive_ms,omitempty"`
 }
 
 // NetworkResponseBodyData holds data for network.response_body events.
@@ -159,18 +533,205 @@ type NetworkFailureData struct {
 	CORSError  interface{} `json:"cors_error"`
 }
 
+// SSEMessageData holds data for network.sse_message events.
+type SSEMessageData struct {
+	RequestID  string `json:"request_id"`
+	URL        string `json:"url"`
+	EventName  string `json:"event_name"`
+	EventID    string `json:"event_id"`
+	Data       string `json:"data,omitempty"`
+	DataLength int    `json:"data_length"`
+	Truncated  bool   `json:"truncated"`
+}
+
+// WebSocketOpenData holds data for network.ws_open events.
+type WebSocketOpenData struct {
+	RequestID string                `json:"request_id"`
+	URL       string                `json:"url"`
+	Initiator *NetworkInitiatorData `json:"initiator,omitempty"`
+}
+
+// WebSocketFrameData holds data for network.ws_frame_sent and
+// network.ws_frame_received events.
+type WebSocketFrameData struct {
+	RequestID     string `json:"request_id"`
+	URL           string `json:"url"`
+	Frame         int64  `json:"frame,omitempty"` // 0 for sockets opened before monitoring
+	Opcode        int64  `json:"opcode"`
+	Payload       string `json:"payload,omitempty"`
+	PayloadLength int    `json:"payload_length"`
+	Truncated     bool   `json:"truncated"`
+}
+
+// WebSocketCloseData holds data for network.ws_close events.
+type WebSocketCloseData struct {
+	RequestID      string `json:"request_id"`
+	URL            string `json:"url"`
+	FramesSent     int64  `json:"frames_sent"`
+	FramesReceived int64  `json:"frames_received"`
+}
+
+// WebSocketErrorData holds data for network.ws_error events.
+type WebSocketErrorData struct {
+	RequestID    string `json:"request_id"`
+	URL          string `json:"url"`
+	ErrorMessage string `json:"error_message"`
+}
+
 // ConsoleData holds data for console.* events.
 type ConsoleData struct {
-	Args []interface{} `json:"args"`
+	Args       []interface{} `json:"args"`
+	Stack      []StackFrame  `json:"stack,omitempty"`
+	GroupDepth int           `json:"group_depth,omitempty"`
+
+	// Label is the group label, or the counter or timer name for
+	// console.count and console.time_end.
+	Label      string  `json:"label,omitempty"`
+	Count      int     `json:"count,omitempty"`
+	DurationMs float64 `json:"duration_ms,omitempty"`
+
+	// Rows holds console.table data, one map per row keyed by column.
+	Rows    []map[string]interface{} `json:"rows,omitempty"`
+	Columns []string                 `json:"columns,omitempty"`
 }
 
-// RuntimeErrorData holds data for error.runtime events.
-type RuntimeErrorData struct {
-	Text     string `json:"text"`
+// StackFrame is a single JavaScript call frame. Line and Column are 0-based,
+// as reported by CDP. AsyncBoundary is set on the first frame after an async
+// hop and names the operation that scheduled it (e.g. "Promise.then").
+type StackFrame struct {
+	Function      string            `json:"function"`
+	URL           string            `json:"url"`
+	Line          int64             `json:"line"`
+	Column        int64             `json:"column"`
+	ScriptID      string            `json:"script_id,omitempty"`
+	AsyncBoundary string            `json:"async_boundary,omitempty"`
+	Original      *OriginalPosition `json:"original,omitempty"`
+}
+
+// OriginalPosition is a location in original source, resolved through a
+// source map. Line and Column are 0-based. Function is the original name
+// mapped at the location, when the map records one.
+type OriginalPosition struct {
+	File     string `json:"file"`
 	Line     int64  `json:"line"`
 	Column   int64  `json:"column"`
-	URL      string `json:"url"`
-	ScriptID string `json:"script_id"`
+	Function string `json:"function,omitempty"`
+}
+
+// LogEntryData holds data for log.entry and console.verbose events.
+// Level uses the same severity names as console.* events
+// (verbose, info, warn, error).
+type LogEntryData struct {
+	Source    string `json:"source"`
+	Level     string `json:"level"`
+	Category  string `json:"category,omitempty"`
+	Text      string `json:"text"`
+	URL       string `json:"url,omitempty"`
+	Line      int64  `json:"line,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// AuditIssueData holds data for audit.issue events.
+// Details holds the issue-specific fields as reported by Chrome.
+type AuditIssueData struct {
+	Code      string                 `json:"code"`
+	IssueID   string                 `json:"issue_id,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Details   map[string]interface{} `json:"details"`
+}
+
+// RuntimeErrorData holds data for error.runtime events.
+type RuntimeErrorData struct {
+	Text             string            `json:"text"`
+	Line             int64             `json:"line"`
+	Column           int64             `json:"column"`
+	URL              string            `json:"url"`
+	ScriptID         string            `json:"script_id"`
+	ExceptionClass   string            `json:"exception_class,omitempty"`
+	ExceptionMessage string            `json:"exception_message,omitempty"`
+	Stack            []StackFrame      `json:"stack,omitempty"`
+	Original         *OriginalPosition `json:"original,omitempty"`
+}
+
+// UnhandledPromiseData holds data for error.unhandled_promise events.
+// Reason is the rejection value; ExceptionID matches the exception_id of a
+// later error.promise_handled event if a handler is attached afterwards.
+type UnhandledPromiseData struct {
+	ExceptionID   int64             `json:"exception_id"`
+	Text          string            `json:"text"`
+	Reason        interface{}       `json:"reason"`
+	ReasonClass   string            `json:"reason_class,omitempty"`
+	ReasonMessage string            `json:"reason_message,omitempty"`
+	Line          int64             `json:"line"`
+	Column        int64             `json:"column"`
+	URL           string            `json:"url"`
+	ScriptID      string            `json:"script_id"`
+	Stack         []StackFrame      `json:"stack,omitempty"`
+	Original      *OriginalPosition `json:"original,omitempty"`
+}
+
+// PromiseHandledData holds data for error.promise_handled events.
+type PromiseHandledData struct {
+	ExceptionID int64  `json:"exception_id"`
+	Reason      string `json:"reason"`
+}
+
+// WebVitalsData holds data for perf.web_vitals events.
+// Reason is "load" for the report after page load and "hidden" for reports
+// when the page is hidden; later reports supersede earlier ones. Timings are
+// in milliseconds from navigation start; zero means not yet measured.
+type WebVitalsData struct {
+	Reason     string              `json:"reason"`
+	URL        string              `json:"url"`
+	LCPMs      float64             `json:"lcp_ms"`
+	LCPElement string              `json:"lcp_element,omitempty"`
+	LCPURL     string              `json:"lcp_url,omitempty"`
+	CLS        float64             `json:"cls"`
+	CLSSources []LayoutShiftSource `json:"cls_sources,omitempty"`
+	INPMs      float64             `json:"inp_ms"`
+	INPEvent   string              `json:"inp_event,omitempty"`
+	INPTarget  string              `json:"inp_target,omitempty"`
+	FCPMs      float64             `json:"fcp_ms"`
+	TTFBMs     float64             `json:"ttfb_ms"`
+}
+
+// LayoutShiftSource is an element that contributed to CLS, with its share
+// of the layout shift score.
+type LayoutShiftSource struct {
+	Selector string  `json:"selector"`
+	Value    float64 `json:"value"`
+}
+
+// LongTaskData holds data for perf.long_task events.
+// Name is the Long Tasks API attribution ("self", "same-origin-descendant",
+// "cross-origin-ancestor", ...); the container fields identify the iframe the
+// task ran in, if it was not the top-level document.
+type LongTaskData struct {
+	URL           string  `json:"url"`
+	Name          string  `json:"name"`
+	StartTimeMs   float64 `json:"start_time_ms"`
+	DurationMs    float64 `json:"duration_ms"`
+	ContainerType string  `json:"container_type,omitempty"`
+	ContainerSrc  string  `json:"container_src,omitempty"`
+	ContainerID   string  `json:"container_id,omitempty"`
+	ContainerName string  `json:"container_name,omitempty"`
+}
+
+// PerfMetricsData holds data for perf.metrics events.
+// Counts and durations are cumulative since the document was created.
+type PerfMetricsData struct {
+	JSHeapUsedBytes       int64   `json:"js_heap_used_bytes"`
+	JSHeapTotalBytes      int64   `json:"js_heap_total_bytes"`
+	Nodes                 int64   `json:"nodes"`
+	Documents             int64   `json:"documents"`
+	Frames                int64   `json:"frames"`
+	JSEventListeners      int64   `json:"js_event_listeners"`
+	LayoutCount           int64   `json:"layout_count"`
+	RecalcStyleCount      int64   `json:"recalc_style_count"`
+	LayoutDurationMs      float64 `json:"layout_duration_ms"`
+	RecalcStyleDurationMs float64 `json:"recalc_style_duration_ms"`
+	ScriptDurationMs      float64 `json:"script_duration_ms"`
+	TaskDurationMs        float64 `json:"task_duration_ms"`
 }
 
 // NewSessionStartEvent creates a meta.session_start event.
@@ -219,6 +780,38 @@ func NewSiteEnteredEvent(site, tabID, fromSite, url string) *LogEvent {
 	})
 }
 
+// NewWorkerAttachedEvent creates a meta.worker_attached event.
+func NewWorkerAttachedEvent(site, tabID, workerID, workerType, url string) *LogEvent {
+	return NewLogEvent(site, tabID, EventMetaWorkerAttached, &WorkerAttachedData{
+		WorkerID:   workerID,
+		WorkerType: workerType,
+		URL:        url,
+	})
+}
+
+// NewWorkerDetachedEvent creates a meta.worker_detached event.
+func NewWorkerDetachedEvent(site, tabID, workerID, workerType string, durationSeconds float64) *LogEvent {
+	return NewLogEvent(site, tabID, EventMetaWorkerDetached, &WorkerDetachedData{
+		WorkerID:        workerID,
+		WorkerType:      workerType,
+		DurationSeconds: durationSeconds,
+	})
+}
+
+// NewLogRotatedEvent creates a meta.log_rotated event.
+func NewLogRotatedEvent(site, tabID, reason, rotatedFile string, sizeBytes int64) *LogEvent {
+	return NewLogEvent(site, tabID, EventMetaLogRotated, &LogRotatedData{
+		Reason:      reason,
+		RotatedFile: rotatedFile,
+		SizeBytes:   sizeBytes,
+	})
+}
+
+// NewRetentionPrunedEvent creates a meta.retention_pruned event.
+func NewRetentionPrunedEvent(data *RetentionPrunedData) *LogEvent {
+	return NewLogEvent("_meta", "_session", EventMetaRetentionPruned, data)
+}
+
 // NewPageNavigateEvent creates a page.navigate event.
 func NewPageNavigateEvent(site, tabID, url, referrer, navigationType string) *LogEvent {
 	return NewLogEvent(site, tabID, EventPageNavigate, &PageNavigateData{
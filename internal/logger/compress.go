@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression formats for log files. The logger only writes them; reading
+// compressed logs back is left to standard tools such as zcat and zstdcat.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// compressedExts maps each compression format to its file extension.
+var compressedExts = map[string]string{
+	CompressionGzip: ".gz",
+	CompressionZstd: ".zst",
+}
+
+// compressor is a compressing writer that can end a block on demand, so
+// readers of a file that is still being written see whole lines.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// newCompressor returns a writer that compresses into w.
+func newCompressor(format string, w io.Writer) (compressor, error) {
+	switch format {
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	default:
+		return nil, fmt.Errorf("unknown compression %q", format)
+	}
+}
+
+// CompressedExt returns the file extension for a compression format, or ""
+// for uncompressed files.
+func CompressedExt(format string) string {
+	return compressedExts[format]
+}
+
+// trimCompressedExt removes a .gz or .zst extension from path.
+func trimCompressedExt(path string) string {
+	for _, ext := range compressedExts {
+		if strings.HasSuffix(path, ext) {
+			return strings.TrimSuffix(path, ext)
+		}
+	}
+	return path
+}
+
+// compressFile compresses a finished log file into path plus the format's
+// extension and removes the original. Compress-on-close moves an existing
+// archive aside first (see rotateArchive); should one still be there, the new
+// stream is appended to it, and on failure it is truncated back and the
+// original is kept.
+func compressFile(path, format string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dstPath := path + CompressedExt(format)
+	dst, err := os.OpenFile(dstPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := dst.Stat()
+	if err != nil {
+		dst.Close()
+		return err
+	}
+
+	err = func() error {
+		enc, err := newCompressor(format, dst)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(enc, src); err != nil {
+			enc.Close()
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+		return dst.Sync()
+	}()
+	if err != nil {
+		_ = dst.Truncate(info.Size())
+		dst.Close()
+		return fmt.Errorf("compress %s: %w", path, err)
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// compressesOnClose reports whether finished files are compressed after
+// they are closed, rather than written compressed.
+func (fm *FileManager) compressesOnClose() bool {
+	return fm.compression != CompressionNone && !fm.compressStream
+}
+
+// markCompressing records that path is about to be compressed, so getWriter
+// won't reopen it and retention won't remove it until the returned channel
+// is closed. It returns nil when files are not compressed on close. Closing
+// tabs call it while still holding fm.mu, so the file can't be reopened
+// before it's marked.
+func (fm *FileManager) markCompressing(path string) chan struct{} {
+	if !fm.compressesOnClose() {
+		return nil
+	}
+	done := make(chan struct{})
+	fm.compressMu.Lock()
+	fm.compressing[path] = done
+	fm.compressMu.Unlock()
+	return done
+}
+
+// rotateArchive renames an existing archive of path, such as the
+// session.log.gz left when the tab's log was last closed, to the next
+// rotated name. Each archive then holds one stretch of logging, rather than
+// every run that reopened the tab's session.log.
+func (fm *FileManager) rotateArchive(path string) error {
+	archive := path + CompressedExt(fm.compression)
+	if !fileExists(archive) {
+		return nil
+	}
+	target, err := rotatedLogPath(archive, fm.rotation.Timestamped, time.Now())
+	if err != nil {
+		return err
+	}
+	return os.Rename(archive, target)
+}
+
+// compressInBackground compresses a finished log file without blocking the
+// caller. done is the channel from markCompressing and is closed once the
+// file is compressed.
+func (fm *FileManager) compressInBackground(path string, done chan struct{}) {
+	if done == nil {
+		return
+	}
+
+	fm.compressWG.Add(1)
+	go func() {
+		defer fm.compressWG.Done()
+		if err := fm.rotateArchive(path); err != nil {
+			log.Printf("Warning: failed to rotate compressed log file: %v", err)
+		} else if err := compressFile(path, fm.compression); err != nil {
+			log.Printf("Warning: failed to compress log file: %v", err)
+		}
+		fm.compressMu.Lock()
+		delete(fm.compressing, path)
+		fm.compressMu.Unlock()
+		close(done)
+	}()
+}
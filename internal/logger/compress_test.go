@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// eventTypes returns the event types in a log file.
+func eventTypes(t *testing.T, path string) []string {
+	t.Helper()
+	var types []string
+	for _, ev := range readLogLines(t, path) {
+		types = append(types, ev["event_type"].(string))
+	}
+	return types
+}
+
+// openLogFile opens a log file for reading, decompressing .gz and .zst files.
+// Files holding several compressed streams (one per time the file was
+// appended to) are read as one.
+func openLogFile(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasSuffix(path, compressedExts[CompressionGzip]):
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("open %s: %w", path, err)
+		}
+		return &decompressingReader{Reader: zr, close: zr.Close, file: f}, nil
+
+	case strings.HasSuffix(path, compressedExts[CompressionZstd]):
+		zr, err := zstd.NewReader(f, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("open %s: %w", path, err)
+		}
+		return &decompressingReader{Reader: zr, close: func() error { zr.Close(); return nil }, file: f}, nil
+	}
+
+	return f, nil
+}
+
+// decompressingReader closes both a decompressor and its underlying file.
+type decompressingReader struct {
+	io.Reader
+	close func() error
+	file  *os.File
+}
+
+// Close closes the decompressor and the file.
+func (r *decompressingReader) Close() error {
+	err := r.close()
+	if ferr := r.file.Close(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+func TestFileManagerCompressOnClose(t *testing.T) {
+	for _, format := range []string{CompressionGzip, CompressionZstd} {
+		t.Run(format, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			fm := NewFileManager(tmpDir)
+			fm.SetCompression(format, false)
+
+			if err := fm.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.navigate", nil)); err != nil {
+				t.Fatalf("WriteEvent failed: %v", err)
+			}
+			if err := fm.CloseTab("tab-1", "example.com"); err != nil {
+				t.Fatalf("CloseTab failed: %v", err)
+			}
+
+			// Returning to the site waits for the first file to be compressed;
+			// closing again moves that archive aside rather than appending to it
+			if err := fm.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.load", nil)); err != nil {
+				t.Fatalf("WriteEvent failed: %v", err)
+			}
+			if err := fm.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			path := GetLogPath(tmpDir, "example.com", "tab-1")
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("expected %s to be removed after compression", path)
+			}
+
+			first := eventTypes(t, filepath.Join(filepath.Dir(path), "session.1.log"+CompressedExt(format)))
+			if len(first) != 1 || first[0] != "page.navigate" {
+				t.Errorf("expected first archive to hold page.navigate, got %v", first)
+			}
+			second := eventTypes(t, path+CompressedExt(format))
+			if len(second) != 1 || second[0] != "page.load" {
+				t.Errorf("expected current archive to hold page.load, got %v", second)
+			}
+		})
+	}
+}
+
+func TestFileManagerCompressRotated(t *testing.T) {
+	tmpDir := t.TempDir()
+	fm := NewFileManager(tmpDir)
+	fm.SetRotation(RotationPolicy{MaxBytes: 300})
+	fm.SetCompression(CompressionGzip, false)
+
+	for i := 0; i < 3; i++ {
+		if err := fm.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.navigate", nil)); err != nil {
+			t.Fatalf("WriteEvent failed: %v", err)
+		}
+	}
+	if err := fm.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	dir := filepath.Join(tmpDir, "example.com", "tab-1")
+	got := eventTypes(t, filepath.Join(dir, "session.1.log.gz"))
+	if got[len(got)-1] != events.EventMetaLogRotated {
+		t.Errorf("expected rotated archive to end with %s, got %v", events.EventMetaLogRotated, got)
+	}
+
+	current := readLogLines(t, filepath.Join(dir, "session.log.gz"))
+	data := current[0]["data"].(map[string]interface{})
+	if data["rotated_file"] != "session.1.log.gz" {
+		t.Errorf("expected rotated_file to name the compressed file, got %v", data["rotated_file"])
+	}
+
+	// The next rotation must not reuse a number taken by a compressed file
+	next, err := rotatedLogPath(filepath.Join(dir, "session.log"), false, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(next) != "session.2.log" {
+		t.Errorf("rotatedLogPath reused a compressed file's number: %s", next)
+	}
+}
+
+func TestFileManagerCompressStream(t *testing.T) {
+	tmpDir := t.TempDir()
+	fm := NewFileManager(tmpDir)
+	fm.SetCompression(CompressionGzip, true)
+
+	// Meta events are flushed immediately, so the line must be readable
+	// while the stream is still open
+	if err := fm.WriteEvent("tab-1", events.NewTabCreatedEvent("example.com", "tab-1", "session", "target", "", "")); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+
+	path := GetLogPath(tmpDir, "example.com", "tab-1") + ".gz"
+	r, err := openLogFile(path)
+	if err != nil {
+		t.Fatalf("openLogFile failed: %v", err)
+	}
+	line, err := bufio.NewReader(r).ReadString('\n')
+	r.Close()
+	if err != nil || !strings.Contains(line, events.EventMetaTabCreated) {
+		t.Fatalf("expected a whole line before the stream is closed, got %q (%v)", line, err)
+	}
+
+	if err := fm.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.load", nil)); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+	if err := fm.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if got := eventTypes(t, path); len(got) != 2 {
+		t.Errorf("expected 2 events, got %v", got)
+	}
+	if _, err := os.Stat(GetLogPath(tmpDir, "example.com", "tab-1")); !os.IsNotExist(err) {
+		t.Error("expected no uncompressed session.log when streaming")
+	}
+}
+
+func TestFileManagerCompressStreamRotated(t *testing.T) {
+	tmpDir := t.TempDir()
+	fm := NewFileManager(tmpDir)
+	fm.SetRotation(RotationPolicy{MaxBytes: 300})
+	fm.SetCompression(CompressionZstd, true)
+
+	for i := 0; i < 3; i++ {
+		if err := fm.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.navigate", nil)); err != nil {
+			t.Fatalf("WriteEvent failed: %v", err)
+		}
+	}
+	if err := fm.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	dir := filepath.Join(tmpDir, "example.com", "tab-1")
+	got := eventTypes(t, filepath.Join(dir, "session.1.log.zst"))
+	if got[len(got)-1] != events.EventMetaLogRotated {
+		t.Errorf("expected rotated stream to end with %s, got %v", events.EventMetaLogRotated, got)
+	}
+	if got := eventTypes(t, filepath.Join(dir, "session.log.zst")); got[0] != events.EventMetaLogRotated {
+		t.Errorf("expected new stream to start with %s, got %v", events.EventMetaLogRotated, got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,11 +26,15 @@ const (
 // tabWriter manages a single log file for a tab.
 type tabWriter struct {
 	file       *os.File
+	enc        compressor // compresses between writer and file, if streaming
 	writer     *bufio.Writer
 	flushTimer *time.Timer
 	mu         sync.Mutex
 	site       string
 	tabID      string
+	path       string
+	size       int64     // bytes in the file, including buffered writes
+	openedAt   time.Time // when the current file was opened, for MaxAge
 }
 
 // FileManager manages log files for all tabs.
@@ -39,6 +44,14 @@ type FileManager struct {
 	mu            sync.RWMutex
 	flushInterval time.Duration
 	bufferSize    int
+	rotation      RotationPolicy
+	opened        map[string]bool // log paths opened by this process
+
+	compression    string
+	compressStream bool
+	compressing    map[string]chan struct{} // closed paths being compressed
+	compressMu     sync.Mutex               // guards compressing; taken after mu
+	compressWG     sync.WaitGroup
 }
 
 // NewFileManager creates a new FileManager with the specified base directory.
@@ -46,8 +59,11 @@ func NewFileManager(baseDir string) *FileManager {
 	return &FileManager{
 		baseDir:       baseDir,
 		files:         make(map[string]*tabWriter),
+		opened:        make(map[string]bool),
 		flushInterval: DefaultFlushInterval,
 		bufferSize:    DefaultBufferSize,
+		compression:   CompressionNone,
+		compressing:   make(map[string]chan struct{}),
 	}
 }
 
@@ -61,6 +77,32 @@ func (fm *FileManager) SetBufferSize(size int) {
 	fm.bufferSize = size
 }
 
+// SetRotation sets the policy used to roll over session.log files.
+func (fm *FileManager) SetRotation(policy RotationPolicy) {
+	fm.rotation = policy
+}
+
+// SetCompression sets how log files are compressed: "none", "gzip" or
+// "zstd". By default files are compressed once they are closed or rotated;
+// with stream set they are written compressed as session.log.gz or
+// session.log.zst, ending a compressed block at every flush.
+func (fm *FileManager) SetCompression(format string, stream bool) {
+	if format == "" {
+		format = CompressionNone
+	}
+	fm.compression = format
+	fm.compressStream = stream && format != CompressionNone
+}
+
+// logPath returns the path of the file a tab writes to on a site.
+func (fm *FileManager) logPath(tabID, site string) string {
+	path := GetLogPath(fm.baseDir, site, tabID)
+	if fm.compressStream {
+		path += CompressedExt(fm.compression)
+	}
+	return path
+}
+
 // fileKey returns the key used to identify a file in the files map.
 func fileKey(tabID, site string) string {
 	return tabID + ":" + site
@@ -86,29 +128,84 @@ func (fm *FileManager) getWriter(tabID, site string) (*tabWriter, error) {
 	}
 
 	// Create log file
-	path := GetLogPath(fm.baseDir, site, tabID)
+	path := fm.logPath(tabID, site)
 	dir := filepath.Dir(path)
 
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return nil, err
 	}
 
-	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	if err != nil {
-		return nil, err
+	// Wait for a previous file at this path to finish compressing
+	for {
+		fm.compressMu.Lock()
+		done, busy := fm.compressing[path]
+		fm.compressMu.Unlock()
+		if !busy {
+			break
+		}
+		fm.mu.Unlock()
+		<-done
+		fm.mu.Lock()
+		if tw, exists := fm.files[key]; exists {
+			return tw, nil
+		}
 	}
 
 	tw := &tabWriter{
-		file:   f,
-		writer: bufio.NewWriterSize(f, fm.bufferSize),
-		site:   site,
-		tabID:  tabID,
+		site:  site,
+		tabID: tabID,
+		path:  path,
+	}
+	if err := fm.openFile(tw); err != nil {
+		return nil, err
+	}
+
+	// Only a file left by a previous run is rotated on start, not one this
+	// process closed and is reopening
+	firstOpen := !fm.opened[path]
+	fm.opened[path] = true
+	if firstOpen && fm.rotation.OnStart && tw.size > 0 {
+		if err := fm.rotate(tw, RotateReasonSessionStart); err != nil {
+			_ = tw.closeFile()
+			return nil, err
+		}
 	}
 
 	fm.files[key] = tw
 	return tw, nil
 }
 
+// openFile opens tw.path for appending and sets up its writers.
+func (fm *FileManager) openFile(tw *tabWriter) error {
+	f, err := os.OpenFile(tw.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	var w io.Writer = f
+	tw.enc = nil
+	if fm.compressStream {
+		enc, err := newCompressor(fm.compression, f)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		tw.enc = enc
+		w = enc
+	}
+
+	tw.file = f
+	tw.writer = bufio.NewWriterSize(w, fm.bufferSize)
+	tw.size = info.Size()
+	tw.openedAt = time.Now()
+	return nil
+}
+
 // WriteEvent writes a log event to the appropriate file.
 func (fm *FileManager) WriteEvent(tabID string, event *events.LogEvent) error {
 	tw, err := fm.getWriter(tabID, event.Site)
@@ -125,13 +222,26 @@ func (fm *FileManager) WriteEvent(tabID string, event *events.LogEvent) error {
 		return err
 	}
 
+	data = append(data, '\n')
+
+	// A failed rotation still leaves a writable file, so the event is
+	// written before the error is reported
+	var rotateErr error
+	if reason := fm.rotationReason(tw, len(data)); reason != "" {
+		rotateErr = fm.rotate(tw, reason)
+	}
+
 	// Write with newline
-	if _, err := tw.writer.Write(append(data, '\n')); err != nil {
+	if _, err := tw.writer.Write(data); err != nil {
 		return err
 	}
+	tw.size += int64(len(data))
 
 	// Smart flush strategy based on event type and buffer state
-	return fm.handleFlush(tw, event.EventType)
+	if err := fm.handleFlush(tw, event.EventType); err != nil {
+		return err
+	}
+	return rotateErr
 }
 
 // handleFlush determines and executes the appropriate flush strategy.
@@ -142,7 +252,7 @@ func (fm *FileManager) handleFlush(tw *tabWriter, eventType string) error {
 	switch {
 	case isMeta:
 		// Meta events MUST be synced immediately (tab lifecycle critical)
-		if err := tw.writer.Flush(); err != nil {
+		if err := tw.flush(); err != nil {
 			return err
 		}
 		if err := tw.file.Sync(); err != nil {
@@ -151,7 +261,7 @@ func (fm *FileManager) handleFlush(tw *tabWriter, eventType string) error {
 		tw.cancelFlushTimer()
 	case bufferFull:
 		// Buffer nearly full, flush to OS (but don't sync to disk)
-		if err := tw.writer.Flush(); err != nil {
+		if err := tw.flush(); err != nil {
 			return err
 		}
 		tw.cancelFlushTimer()
@@ -172,11 +282,50 @@ func (tw *tabWriter) scheduleFlush(interval time.Duration) {
 	tw.flushTimer = time.AfterFunc(interval, func() {
 		tw.mu.Lock()
 		defer tw.mu.Unlock()
-		_ = tw.writer.Flush()
+		if tw.flushTimer == nil {
+			return // Cancelled while waiting for the lock
+		}
+		_ = tw.flush()
 		tw.flushTimer = nil
 	})
 }
 
+// flush writes buffered lines through to the file. When streaming
+// compressed output it also ends the compressed block, so readers of the
+// file see every whole line written so far.
+func (tw *tabWriter) flush() error {
+	if err := tw.writer.Flush(); err != nil {
+		return err
+	}
+	if tw.enc != nil {
+		return tw.enc.Flush()
+	}
+	return nil
+}
+
+// closeFile flushes, syncs and closes tw's file, finishing any compressed
+// stream.
+func (tw *tabWriter) closeFile() error {
+	tw.cancelFlushTimer()
+
+	var errs []error
+	if err := tw.writer.Flush(); err != nil {
+		errs = append(errs, fmt.Errorf("flush: %w", err))
+	}
+	if tw.enc != nil {
+		if err := tw.enc.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("compress: %w", err))
+		}
+	}
+	if err := tw.file.Sync(); err != nil {
+		errs = append(errs, fmt.Errorf("sync: %w", err))
+	}
+	if err := tw.file.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
 // cancelFlushTimer cancels any pending flush timer.
 func (tw *tabWriter) cancelFlushTimer() {
 	if tw.flushTimer != nil {
@@ -196,22 +345,16 @@ func (fm *FileManager) CloseTab(tabID, site string) error {
 		return nil
 	}
 	delete(fm.files, key)
+	done := fm.markCompressing(tw.path)
 	fm.mu.Unlock()
 
 	tw.mu.Lock()
 	defer tw.mu.Unlock()
 
 	// Final flush and sync
-	tw.cancelFlushTimer()
-
-	if err := tw.writer.Flush(); err != nil {
-		return err
-	}
-	if err := tw.file.Sync(); err != nil {
-		return err
-	}
-
-	return tw.file.Close()
+	err := tw.closeFile()
+	fm.compressInBackground(tw.path, done)
+	return err
 }
 
 // CloseAllForTab closes all log files for a specific tab (all sites).
@@ -230,29 +373,13 @@ func (fm *FileManager) CloseAllForTab(tabID string) error {
 	for _, key := range keysToDelete {
 		delete(fm.files, key)
 	}
+	dones := fm.markAllCompressing(toClose)
 	fm.mu.Unlock()
 
-	var errs []error
-	for _, tw := range toClose {
-		tw.mu.Lock()
-		tw.cancelFlushTimer()
-
-		if err := tw.writer.Flush(); err != nil {
-			errs = append(errs, fmt.Errorf("flush %s/%s: %w", tw.site, tw.tabID, err))
-		}
-		if err := tw.file.Sync(); err != nil {
-			errs = append(errs, fmt.Errorf("sync %s/%s: %w", tw.site, tw.tabID, err))
-		}
-		if err := tw.file.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("close %s/%s: %w", tw.site, tw.tabID, err))
-		}
-		tw.mu.Unlock()
-	}
-
-	return errors.Join(errs...)
+	return fm.closeWriters(toClose, dones)
 }
 
-// Close closes all open log files.
+// Close closes all open log files and waits for them to be compressed.
 func (fm *FileManager) Close() error {
 	fm.mu.Lock()
 	writers := make([]*tabWriter, 0, len(fm.files))
@@ -260,28 +387,80 @@ func (fm *FileManager) Close() error {
 		writers = append(writers, tw)
 	}
 	fm.files = make(map[string]*tabWriter)
+	dones := fm.markAllCompressing(writers)
 	fm.mu.Unlock()
 
+	err := fm.closeWriters(writers, dones)
+	fm.compressWG.Wait()
+	return err
+}
+
+// markAllCompressing calls markCompressing for each writer's file.
+// fm.mu must be held.
+func (fm *FileManager) markAllCompressing(writers []*tabWriter) []chan struct{} {
+	dones := make([]chan struct{}, len(writers))
+	for i, tw := range writers {
+		dones[i] = fm.markCompressing(tw.path)
+	}
+	return dones
+}
+
+// closeWriters closes writers that have been removed from fm.files, then
+// compresses their files. dones comes from markAllCompressing.
+func (fm *FileManager) closeWriters(writers []*tabWriter, dones []chan struct{}) error {
 	var errs []error
-	for _, tw := range writers {
+	for i, tw := range writers {
 		tw.mu.Lock()
-		tw.cancelFlushTimer()
-
-		if err := tw.writer.Flush(); err != nil {
-			errs = append(errs, fmt.Errorf("flush %s/%s: %w", tw.site, tw.tabID, err))
-		}
-		if err := tw.file.Sync(); err != nil {
-			errs = append(errs, fmt.Errorf("sync %s/%s: %w", tw.site, tw.tabID, err))
-		}
-		if err := tw.file.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("close %s/%s: %w", tw.site, tw.tabID, err))
+		if err := tw.closeFile(); err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", tw.site, tw.tabID, err))
 		}
+		fm.compressInBackground(tw.path, dones[i])
 		tw.mu.Unlock()
 	}
 
 	return errors.Join(errs...)
 }
 
+// InUse reports whether a log file is open for writing or is being
+// compressed, and so must not be removed.
+func (fm *FileManager) InUse(path string) bool {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+	return fm.inUseLocked(filepath.Clean(path))
+}
+
+// removeUnused removes a log file unless it is in use, and reports whether
+// it was. fm.mu is held throughout, so the file can't be opened between the
+// check and the removal.
+func (fm *FileManager) removeUnused(path string) (bool, error) {
+	path = filepath.Clean(path)
+
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	if fm.inUseLocked(path) {
+		return true, nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	return false, nil
+}
+
+// inUseLocked is InUse for a cleaned path. The caller holds fm.mu.
+func (fm *FileManager) inUseLocked(path string) bool {
+	for _, tw := range fm.files {
+		if tw.path == path {
+			return true
+		}
+	}
+
+	fm.compressMu.Lock()
+	defer fm.compressMu.Unlock()
+	_, source := fm.compressing[path]
+	_, target := fm.compressing[trimCompressedExt(path)]
+	return source || target
+}
+
 // GetOpenFiles returns the number of currently open log files.
 func (fm *FileManager) GetOpenFiles() int {
 	fm.mu.RLock()
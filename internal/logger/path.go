@@ -15,6 +15,14 @@ import (
 // UnknownSite is the default site name for unknown or invalid URLs.
 const UnknownSite = "unknown"
 
+// WorkersSite is the site name for service and shared workers, which are not
+// owned by a single tab.
+const WorkersSite = "_workers"
+
+// DownloadsDir is the directory under the output directory that saved
+// downloads are written to, one subdirectory per session.
+const DownloadsDir = "_downloads"
+
 var (
 	sessionID   string
 	sessionOnce sync.Once
@@ -171,3 +179,8 @@ func ExtractSite(urlStr string) string {
 func GetLogPath(baseDir, site, tabID string) string {
 	return filepath.Join(baseDir, site, tabID, "session.log")
 }
+
+// GetDownloadDir returns the directory saved downloads are written to for a session.
+func GetDownloadDir(baseDir, sessionID string) string {
+	return filepath.Join(baseDir, DownloadsDir, sessionID)
+}
@@ -193,6 +193,14 @@ func TestGetLogPath(t *testing.T) {
 	}
 }
 
+func TestGetDownloadDir(t *testing.T) {
+	result := GetDownloadDir("./logs", "session-1")
+	expected := filepath.Join("./logs", "_downloads", "session-1")
+	if result != expected {
+		t.Errorf("GetDownloadDir() = %q, want %q", result, expected)
+	}
+}
+
 func TestTabRegistry(t *testing.T) {
 	registry := NewTabRegistry()
 
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// PIDFileName is the file a running browser_tail keeps in its output
+// directory. It holds the process ID, so a standalone prune can tell that
+// the active logs are still being written.
+const PIDFileName = ".browser_tail.pid"
+
+// WritePIDFile records the current process as the writer of the logs under
+// baseDir. The returned function removes the file again, unless another
+// process has taken it over since.
+func WritePIDFile(baseDir string) (func(), error) {
+	path := filepath.Join(baseDir, PIDFileName)
+	pid := strconv.Itoa(os.Getpid())
+	if err := os.WriteFile(path, []byte(pid+"\n"), 0o644); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) == pid {
+			_ = os.Remove(path)
+		}
+	}, nil
+}
+
+// ActiveWriter returns the process ID from baseDir's PID file and whether
+// that process is still running. A PID file that can't be read is assumed
+// to belong to a running process, so its logs are left alone.
+func ActiveWriter(baseDir string) (int, bool) {
+	data, err := os.ReadFile(filepath.Join(baseDir, PIDFileName))
+	if os.IsNotExist(err) {
+		return 0, false
+	}
+	if err != nil {
+		return 0, true
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, true
+	}
+	return pid, processRunning(pid)
+}
+
+// processRunning reports whether pid is a running process. Where signal 0
+// is unsupported, a process that can be found is assumed to be running.
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || !errors.Is(err, os.ErrProcessDone) && !errors.Is(err, syscall.ESRCH)
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWritePIDFile(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, ok := ActiveWriter(dir); ok {
+		t.Error("expected no active writer without a PID file")
+	}
+
+	removePIDFile, err := WritePIDFile(dir)
+	if err != nil {
+		t.Fatalf("WritePIDFile failed: %v", err)
+	}
+	if pid, ok := ActiveWriter(dir); !ok || pid != os.Getpid() {
+		t.Errorf("expected this process to be the active writer, got %d (%v)", pid, ok)
+	}
+
+	removePIDFile()
+	if _, err := os.Stat(filepath.Join(dir, PIDFileName)); !os.IsNotExist(err) {
+		t.Error("expected the PID file to be removed")
+	}
+}
+
+func TestWritePIDFileKeepsNewerOwner(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, PIDFileName)
+
+	removePIDFile, err := WritePIDFile(dir)
+	if err != nil {
+		t.Fatalf("WritePIDFile failed: %v", err)
+	}
+
+	// Another process started on the same directory and took the file over
+	if err := os.WriteFile(path, []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	removePIDFile()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the other process's PID file to be kept: %v", err)
+	}
+}
+
+func TestActiveWriterUnreadablePIDFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, PIDFileName), []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := ActiveWriter(dir); !ok {
+		t.Error("expected an unreadable PID file to be treated as active")
+	}
+}
@@ -0,0 +1,270 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// Reasons reported for files in meta.retention_pruned events.
+const (
+	PruneReasonAge       = "age"
+	PruneReasonSiteFiles = "site_files"
+	PruneReasonTotalSize = "total_size"
+)
+
+// RetentionPolicy limits the log files kept under the output directory. Zero
+// fields are not enforced.
+type RetentionPolicy struct {
+	// MaxAge removes files last written longer ago than this.
+	MaxAge time.Duration
+
+	// MaxTotalBytes removes the oldest files until all logs fit.
+	MaxTotalBytes int64
+
+	// MaxFilesPerSite keeps only the newest log files for each site. Every
+	// file counts, rotated ones included, since tab IDs restart with each
+	// run. browser_tail's own _meta and _workers logs are not limited.
+	MaxFilesPerSite int
+}
+
+// Enabled reports whether the policy limits anything.
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxTotalBytes > 0 || p.MaxFilesPerSite > 0
+}
+
+// logFile is a log file found under the output directory.
+type logFile struct {
+	path    string
+	rel     string
+	site    string
+	size    int64
+	modTime time.Time
+	inUse   bool
+	removed bool
+}
+
+// Prune removes log files under baseDir that the policy doesn't keep: files
+// older than MaxAge, then each site's oldest files beyond
+// MaxFilesPerSite, then the oldest files overall until the total is under
+// MaxTotalBytes. Files fm has open or is compressing are never removed but
+// still count toward the limits. Without a FileManager, active session.log
+// files are treated as open while another browser_tail process has a PID
+// file in baseDir, and otherwise directories left empty are removed. With
+// dryRun nothing is removed.
+func Prune(baseDir string, policy RetentionPolicy, fm *FileManager, dryRun bool) (*events.RetentionPrunedData, error) {
+	now := time.Now()
+	_, writerActive := ActiveWriter(baseDir)
+	files, err := findLogFiles(baseDir, fm, writerActive)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &events.RetentionPrunedData{Files: []events.PrunedFile{}}
+	var errs []error
+	remove := func(f *logFile, reason string) {
+		if f.inUse || f.removed {
+			return
+		}
+		// Check again: the file may have been reopened since it was found
+		switch {
+		case fm != nil && dryRun:
+			f.inUse = fm.InUse(f.path)
+		case fm != nil:
+			var err error
+			if f.inUse, err = fm.removeUnused(f.path); err != nil {
+				errs = append(errs, err)
+				return
+			}
+		case !dryRun:
+			if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+				return
+			}
+		}
+		if f.inUse {
+			return
+		}
+		f.removed = true
+		result.BytesFreed += f.size
+		result.Files = append(result.Files, events.PrunedFile{
+			Path:       filepath.ToSlash(f.rel),
+			Site:       f.site,
+			Reason:     reason,
+			SizeBytes:  f.size,
+			ModifiedAt: f.modTime.UTC().Format(time.RFC3339),
+		})
+	}
+
+	// Oldest first
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	if policy.MaxAge > 0 {
+		for _, f := range files {
+			if now.Sub(f.modTime) > policy.MaxAge {
+				remove(f, PruneReasonAge)
+			}
+		}
+	}
+
+	if policy.MaxFilesPerSite > 0 {
+		bySite := make(map[string][]*logFile)
+		for _, f := range files {
+			if !f.removed && !isInternalSite(f.site) {
+				bySite[f.site] = append(bySite[f.site], f)
+			}
+		}
+		for _, siteFiles := range bySite {
+			for i := 0; i < len(siteFiles)-policy.MaxFilesPerSite; i++ {
+				remove(siteFiles[i], PruneReasonSiteFiles)
+			}
+		}
+	}
+
+	if policy.MaxTotalBytes > 0 {
+		var total int64
+		for _, f := range files {
+			if !f.removed {
+				total += f.size
+			}
+		}
+		for _, f := range files {
+			if total <= policy.MaxTotalBytes {
+				break
+			}
+			remove(f, PruneReasonTotalSize)
+			if f.removed {
+				total -= f.size
+			}
+		}
+	}
+
+	// A running FileManager may be about to create a file in an empty
+	// directory, so directories are only cleaned up when none is running
+	if fm == nil && !writerActive && !dryRun {
+		for _, f := range files {
+			if f.removed {
+				removeEmptyDirs(baseDir, filepath.Dir(f.path))
+			}
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// findLogFiles returns the log files under baseDir, including rotated and
+// compressed ones. With writerActive, active session.log files are marked in
+// use even without a FileManager.
+func findLogFiles(baseDir string, fm *FileManager, writerActive bool) ([]*logFile, error) {
+	baseDir = filepath.Clean(baseDir)
+
+	var files []*logFile
+	err := filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == baseDir {
+				return err
+			}
+			return nil // Skip unreadable entries
+		}
+		if d.IsDir() {
+			if path != baseDir && d.Name() == DownloadsDir && filepath.Dir(path) == baseDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isLogFileName(d.Name()) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			return nil
+		}
+
+		f := &logFile{
+			path:    path,
+			rel:     rel,
+			site:    strings.SplitN(filepath.ToSlash(rel), "/", 2)[0],
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+		if fm != nil {
+			f.inUse = fm.InUse(path)
+		} else {
+			f.inUse = writerActive && isActiveLogName(d.Name())
+		}
+		files = append(files, f)
+		return nil
+	})
+	return files, err
+}
+
+// isLogFileName reports whether name is a session log, rotated or not,
+// compressed or not.
+func isLogFileName(name string) bool {
+	name = trimCompressedExt(name)
+	return strings.HasPrefix(name, "session") && strings.HasSuffix(name, ".log")
+}
+
+// isActiveLogName reports whether name is the file FileManager writes to,
+// rather than a rotated or compressed one. A session.log.gz may be an
+// earlier archive of the same tab, so only the uncompressed file counts.
+func isActiveLogName(name string) bool {
+	return name == "session.log"
+}
+
+// isInternalSite reports whether site is one of browser_tail's own
+// directories, such as _meta or _workers, rather than a site's hostname.
+func isInternalSite(site string) bool {
+	return strings.HasPrefix(site, "_")
+}
+
+// removeEmptyDirs removes dir and its parents up to baseDir while they are
+// empty.
+func removeEmptyDirs(baseDir, dir string) {
+	baseDir = filepath.Clean(baseDir)
+	for dir != baseDir && strings.HasPrefix(dir, baseDir+string(filepath.Separator)) {
+		if os.Remove(dir) != nil {
+			return // Not empty, or already gone
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// RunRetention prunes the output directory when called and then every
+// interval until ctx is done, writing a meta.retention_pruned event whenever
+// files are removed.
+func (fm *FileManager) RunRetention(ctx context.Context, policy RetentionPolicy, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := Prune(fm.baseDir, policy, fm, false)
+		if err != nil {
+			log.Printf("Warning: failed to prune logs: %v", err)
+		}
+		if result != nil && len(result.Files) > 0 {
+			if err := fm.WriteEvent("_session", events.NewRetentionPrunedEvent(result)); err != nil {
+				log.Printf("Warning: failed to write retention event: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
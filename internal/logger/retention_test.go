@@ -0,0 +1,206 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// writeAgedLog creates a log file under dir with the given size and age.
+func writeAgedLog(t *testing.T, dir, rel string, size int, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(-age)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// prunedPaths returns the relative paths in a prune result.
+func prunedPaths(result *events.RetentionPrunedData) map[string]string {
+	paths := make(map[string]string, len(result.Files))
+	for _, f := range result.Files {
+		paths[f.Path] = f.Reason
+	}
+	return paths
+}
+
+func TestPruneMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedLog(t, dir, "example.com/tab-1/session.1.log.gz", 10, 48*time.Hour)
+	writeAgedLog(t, dir, "example.com/tab-1/session.log", 10, time.Hour)
+	writeAgedLog(t, dir, "_downloads/abc/session.log", 10, 48*time.Hour)
+
+	result, err := Prune(dir, RetentionPolicy{MaxAge: 24 * time.Hour}, nil, false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	got := prunedPaths(result)
+	if len(got) != 1 || got["example.com/tab-1/session.1.log.gz"] != PruneReasonAge {
+		t.Errorf("expected only the old rotated file to be pruned, got %v", got)
+	}
+	if result.BytesFreed != 10 {
+		t.Errorf("expected 10 bytes freed, got %d", result.BytesFreed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "_downloads/abc/session.log")); err != nil {
+		t.Errorf("expected downloads to be left alone: %v", err)
+	}
+}
+
+func TestPruneMaxFilesPerSite(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedLog(t, dir, "example.com/tab-1/session.1.log", 10, 3*time.Hour)
+	writeAgedLog(t, dir, "example.com/tab-2/session.1.log", 10, 2*time.Hour)
+	writeAgedLog(t, dir, "example.com/tab-1/session.2.log", 10, time.Hour)
+	writeAgedLog(t, dir, "github.com/tab-3/session.1.log", 10, 5*time.Hour)
+	for i := 1; i <= 3; i++ {
+		writeAgedLog(t, dir, fmt.Sprintf("_meta/_session/session.%d.log", i), 10, 4*time.Hour)
+		writeAgedLog(t, dir, fmt.Sprintf("_workers/worker-%d/session.log", i), 10, 4*time.Hour)
+	}
+
+	result, err := Prune(dir, RetentionPolicy{MaxFilesPerSite: 2}, nil, false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	got := prunedPaths(result)
+	if len(got) != 1 || got["example.com/tab-1/session.1.log"] != PruneReasonSiteFiles {
+		t.Errorf("expected the oldest example.com file to be pruned, got %v", got)
+	}
+}
+
+func TestPruneMaxTotalBytes(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedLog(t, dir, "a.com/tab-1/session.1.log", 100, 3*time.Hour)
+	writeAgedLog(t, dir, "b.com/tab-2/session.1.log", 100, 2*time.Hour)
+	writeAgedLog(t, dir, "c.com/tab-3/session.1.log", 100, time.Hour)
+
+	result, err := Prune(dir, RetentionPolicy{MaxTotalBytes: 150}, nil, false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	got := prunedPaths(result)
+	if len(got) != 2 || got["a.com/tab-1/session.1.log"] != PruneReasonTotalSize || got["b.com/tab-2/session.1.log"] != PruneReasonTotalSize {
+		t.Errorf("expected the two oldest files to be pruned, got %v", got)
+	}
+
+	// Emptied directories are cleaned up when no FileManager is running
+	if _, err := os.Stat(filepath.Join(dir, "a.com")); !os.IsNotExist(err) {
+		t.Errorf("expected empty site directory to be removed")
+	}
+}
+
+func TestPruneSkipsOpenFiles(t *testing.T) {
+	dir := t.TempDir()
+	fm := NewFileManager(dir)
+	defer fm.Close()
+
+	if err := fm.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.load", nil)); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+	open := GetLogPath(dir, "example.com", "tab-1")
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(open, old, old); err != nil {
+		t.Fatal(err)
+	}
+	writeAgedLog(t, dir, "example.com/tab-1/session.1.log", 10, 48*time.Hour)
+
+	result, err := Prune(dir, RetentionPolicy{MaxAge: time.Hour}, fm, false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	got := prunedPaths(result)
+	if len(got) != 1 || got["example.com/tab-1/session.1.log"] == "" {
+		t.Errorf("expected only the closed file to be pruned, got %v", got)
+	}
+	if _, err := os.Stat(open); err != nil {
+		t.Errorf("open file was removed: %v", err)
+	}
+}
+
+func TestPruneStandaloneSkipsActiveFilesWhileRunning(t *testing.T) {
+	dir := t.TempDir()
+	active := writeAgedLog(t, dir, "example.com/tab-1/session.log", 100, 48*time.Hour)
+	rotated := writeAgedLog(t, dir, "example.com/tab-1/session.1.log", 100, 48*time.Hour)
+	archived := writeAgedLog(t, dir, "example.com/tab-1/session.log.gz", 100, 48*time.Hour)
+
+	removePIDFile, err := WritePIDFile(dir)
+	if err != nil {
+		t.Fatalf("WritePIDFile failed: %v", err)
+	}
+	if _, err := Prune(dir, RetentionPolicy{MaxAge: time.Hour}, nil, false); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if _, err := os.Stat(active); err != nil {
+		t.Errorf("session.log of a running process was removed: %v", err)
+	}
+	if _, err := os.Stat(rotated); !os.IsNotExist(err) {
+		t.Errorf("expected rotated file to be removed")
+	}
+	if _, err := os.Stat(archived); !os.IsNotExist(err) {
+		t.Errorf("expected compressed archive to be removed")
+	}
+
+	// Once the process has exited, its session.log is just an old file
+	removePIDFile()
+	if _, err := Prune(dir, RetentionPolicy{MaxAge: time.Hour}, nil, false); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if _, err := os.Stat(active); !os.IsNotExist(err) {
+		t.Errorf("expected session.log to be removed once no process is writing")
+	}
+}
+
+func TestPruneDryRun(t *testing.T) {
+	dir := t.TempDir()
+	path := writeAgedLog(t, dir, "example.com/tab-1/session.1.log", 10, 48*time.Hour)
+
+	result, err := Prune(dir, RetentionPolicy{MaxAge: time.Hour}, nil, true)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Errorf("expected the file to be reported, got %v", result.Files)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("dry run removed a file: %v", err)
+	}
+}
+
+func TestRunRetentionWritesEvent(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedLog(t, dir, "example.com/tab-1/session.1.log", 10, 48*time.Hour)
+
+	fm := NewFileManager(dir)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fm.RunRetention(ctx, RetentionPolicy{MaxAge: time.Hour}, time.Hour)
+	if err := fm.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	lines := readLogLines(t, GetLogPath(dir, "_meta", "_session"))
+	if len(lines) != 1 || lines[0]["event_type"] != events.EventMetaRetentionPruned {
+		t.Fatalf("expected a %s event, got %v", events.EventMetaRetentionPruned, lines)
+	}
+	files := lines[0]["data"].(map[string]interface{})["files"].([]interface{})
+	if len(files) != 1 || files[0].(map[string]interface{})["path"] != "example.com/tab-1/session.1.log" {
+		t.Errorf("unexpected pruned files: %v", files)
+	}
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// Reasons reported in meta.log_rotated events.
+const (
+	RotateReasonSize         = "size"
+	RotateReasonAge          = "age"
+	RotateReasonSessionStart = "session_start"
+)
+
+// rotatedTimeFormat is the timestamp used in timestamped rotated file names.
+const rotatedTimeFormat = "20060102T150405Z"
+
+// RotationPolicy controls when session.log files are rolled over. The zero
+// value never rotates.
+type RotationPolicy struct {
+	// MaxBytes rotates a file before a write would take it past this size.
+	MaxBytes int64
+
+	// MaxAge rotates a file once it has been open for this long.
+	MaxAge time.Duration
+
+	// OnStart rotates a non-empty file left by a previous run the first time
+	// this process opens it.
+	OnStart bool
+
+	// Timestamped names rotated files session.<UTC time>.log instead of
+	// session.<n>.log.
+	Timestamped bool
+}
+
+// rotationReason returns why tw must be rotated before writing n more bytes,
+// or "" if it doesn't need to be.
+func (fm *FileManager) rotationReason(tw *tabWriter, n int) string {
+	if tw.size == 0 {
+		return ""
+	}
+	if fm.rotation.MaxBytes > 0 && tw.size+int64(n) > fm.rotation.MaxBytes {
+		return RotateReasonSize
+	}
+	if fm.rotation.MaxAge > 0 && time.Since(tw.openedAt) >= fm.rotation.MaxAge {
+		return RotateReasonAge
+	}
+	return ""
+}
+
+// rotate renames tw's file out of the way and reopens session.log in its
+// place. A meta.log_rotated event is written as the last line of the old file
+// and the first line of the new one. Renaming and recreating the file keeps
+// readers that follow the name (tail -F) working.
+//
+// tw.mu must be held.
+func (fm *FileManager) rotate(tw *tabWriter, reason string) error {
+	target, err := rotatedLogPath(tw.path, fm.rotation.Timestamped, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Report the name the file will have once compressed
+	rotatedFile := filepath.Base(target)
+	if fm.compressesOnClose() {
+		rotatedFile += CompressedExt(fm.compression)
+	}
+
+	line, err := json.Marshal(events.NewLogRotatedEvent(tw.site, tw.tabID, reason, rotatedFile, tw.size))
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	var errs []error
+	if _, err := tw.writer.Write(line); err != nil {
+		errs = append(errs, err)
+	}
+	if err := tw.closeFile(); err != nil {
+		errs = append(errs, err)
+	}
+	renameErr := os.Rename(tw.path, target)
+	if renameErr != nil {
+		errs = append(errs, renameErr)
+	}
+
+	// Reopen even if the rename failed so the tab keeps logging
+	if err := fm.openFile(tw); err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if renameErr == nil {
+		fm.compressInBackground(target, fm.markCompressing(target))
+	}
+
+	if _, err := tw.writer.Write(line); err != nil {
+		errs = append(errs, err)
+	}
+	tw.size += int64(len(line))
+	if err := tw.flush(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := tw.file.Sync(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("rotate %s: %w", tw.path, err)
+	}
+	return nil
+}
+
+// rotatedLogPath returns an unused name to rotate logPath to: the next
+// session.<n>.log after the highest existing n, or session.<UTC time>.log.
+// A compressed logPath keeps its .gz or .zst extension, and compressed
+// rotated files count as existing.
+func rotatedLogPath(logPath string, timestamped bool, now time.Time) (string, error) {
+	plain := trimCompressedExt(logPath)
+	compressedExt := strings.TrimPrefix(logPath, plain)
+
+	dir := filepath.Dir(plain)
+	ext := filepath.Ext(plain)
+	stem := strings.TrimSuffix(filepath.Base(plain), ext)
+
+	if timestamped {
+		name := stem + "." + now.UTC().Format(rotatedTimeFormat)
+		path := filepath.Join(dir, name+ext)
+		// Rotations within the same second get a counter
+		for i := 1; logFileExists(path); i++ {
+			path = filepath.Join(dir, name+"-"+strconv.Itoa(i)+ext)
+		}
+		return path + compressedExt, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, stem+".*"+ext+"*"))
+	if err != nil {
+		return "", err
+	}
+	next := 1
+	for _, m := range matches {
+		base := strings.TrimSuffix(trimCompressedExt(filepath.Base(m)), ext)
+		n, err := strconv.Atoi(strings.TrimPrefix(base, stem+"."))
+		if err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return filepath.Join(dir, stem+"."+strconv.Itoa(next)+ext) + compressedExt, nil
+}
+
+// logFileExists reports whether a log file exists at path, compressed or not.
+func logFileExists(path string) bool {
+	if fileExists(path) {
+		return true
+	}
+	for _, ext := range compressedExts {
+		if fileExists(path + ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileExists reports whether path exists.
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
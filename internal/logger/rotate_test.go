@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// readLogLines returns the parsed events in a log file, decompressing it
+// if needed.
+func readLogLines(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	r, err := openLogFile(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var ev map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("Line in %s is not valid JSON: %v", path, err)
+		}
+		lines = append(lines, ev)
+	}
+	return lines
+}
+
+func TestFileManagerRotateBySize(t *testing.T) {
+	tmpDir := t.TempDir()
+	fm := NewFileManager(tmpDir)
+	fm.SetRotation(RotationPolicy{MaxBytes: 400})
+
+	for i := 0; i < 6; i++ {
+		event := events.NewLogEvent("example.com", "tab-1", "page.navigate", map[string]interface{}{"index": i})
+		if err := fm.WriteEvent("tab-1", event); err != nil {
+			t.Fatalf("WriteEvent failed: %v", err)
+		}
+	}
+	if err := fm.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	dir := filepath.Join(tmpDir, "example.com", "tab-1")
+	rotated := readLogLines(t, filepath.Join(dir, "session.1.log"))
+	last := rotated[len(rotated)-1]
+	if last["event_type"] != events.EventMetaLogRotated {
+		t.Fatalf("expected rotated file to end with %s, got %v", events.EventMetaLogRotated, last["event_type"])
+	}
+	data := last["data"].(map[string]interface{})
+	if data["reason"] != RotateReasonSize || data["rotated_file"] != "session.1.log" {
+		t.Errorf("unexpected rotation data: %v", data)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "session.2.log")); err != nil {
+		t.Errorf("expected a second rotation: %v", err)
+	}
+
+	current := readLogLines(t, filepath.Join(dir, "session.log"))
+	if current[0]["event_type"] != events.EventMetaLogRotated {
+		t.Errorf("expected new file to start with %s, got %v", events.EventMetaLogRotated, current[0]["event_type"])
+	}
+
+	// Every event is in exactly one file
+	files, _ := filepath.Glob(filepath.Join(dir, "session*.log"))
+	var count int
+	for _, path := range files {
+		for _, ev := range readLogLines(t, path) {
+			if ev["event_type"] == "page.navigate" {
+				count++
+			}
+		}
+	}
+	if count != 6 {
+		t.Errorf("expected 6 events across rotated files, got %d", count)
+	}
+}
+
+func TestFileManagerRotateByAge(t *testing.T) {
+	tmpDir := t.TempDir()
+	fm := NewFileManager(tmpDir)
+	fm.SetRotation(RotationPolicy{MaxAge: time.Hour})
+
+	event := events.NewLogEvent("example.com", "tab-1", "page.navigate", nil)
+	if err := fm.WriteEvent("tab-1", event); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+
+	tw, _ := fm.getWriter("tab-1", "example.com")
+	tw.openedAt = time.Now().Add(-2 * time.Hour)
+
+	if err := fm.WriteEvent("tab-1", event); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+	if err := fm.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	current := readLogLines(t, filepath.Join(tmpDir, "example.com", "tab-1", "session.log"))
+	if len(current) != 2 {
+		t.Fatalf("expected rotation event and one event in new file, got %d lines", len(current))
+	}
+	data := current[0]["data"].(map[string]interface{})
+	if data["reason"] != RotateReasonAge {
+		t.Errorf("expected reason %q, got %v", RotateReasonAge, data["reason"])
+	}
+}
+
+func TestFileManagerRotateOnStart(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := GetLogPath(tmpDir, "example.com", "tab-1")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"event_type":"page.navigate"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fm := NewFileManager(tmpDir)
+	fm.SetRotation(RotationPolicy{OnStart: true, Timestamped: true})
+
+	event := events.NewLogEvent("example.com", "tab-1", "page.navigate", nil)
+	if err := fm.WriteEvent("tab-1", event); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+
+	// Closing and reopening within the same run must not rotate again
+	if err := fm.CloseTab("tab-1", "example.com"); err != nil {
+		t.Fatalf("CloseTab failed: %v", err)
+	}
+	if err := fm.WriteEvent("tab-1", event); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+	if err := fm.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "session.*.log"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one rotated file, got %v", matches)
+	}
+	name := filepath.Base(matches[0])
+	if _, err := time.Parse(rotatedTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, "session."), ".log")); err != nil {
+		t.Errorf("expected a timestamped name, got %s", name)
+	}
+
+	current := readLogLines(t, path)
+	if len(current) != 3 {
+		t.Fatalf("expected rotation event and two events, got %d lines", len(current))
+	}
+	data := current[0]["data"].(map[string]interface{})
+	if data["reason"] != RotateReasonSessionStart || data["rotated_file"] != name {
+		t.Errorf("unexpected rotation data: %v", data)
+	}
+}
+
+func TestFileManagerNoRotationByDefault(t *testing.T) {
+	tmpDir := t.TempDir()
+	fm := NewFileManager(tmpDir)
+
+	for i := 0; i < 100; i++ {
+		if err := fm.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.navigate", nil)); err != nil {
+			t.Fatalf("WriteEvent failed: %v", err)
+		}
+	}
+	if err := fm.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(tmpDir, "example.com", "tab-1", "*.log"))
+	if len(matches) != 1 {
+		t.Errorf("expected only session.log, got %v", matches)
+	}
+}
+
+func TestRotatedLogPath(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "session.log")
+
+	got, err := rotatedLogPath(logPath, false, time.Now())
+	if err != nil || filepath.Base(got) != "session.1.log" {
+		t.Errorf("rotatedLogPath() = %q, %v; want session.1.log", got, err)
+	}
+
+	for _, name := range []string{"session.1.log", "session.7.log", "session.20260101T000000Z.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, _ := rotatedLogPath(logPath, false, time.Now()); filepath.Base(got) != "session.8.log" {
+		t.Errorf("rotatedLogPath() = %q, want session.8.log", got)
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got, _ := rotatedLogPath(logPath, true, now); filepath.Base(got) != "session.20260101T000000Z-1.log" {
+		t.Errorf("rotatedLogPath() = %q, want session.20260101T000000Z-1.log", got)
+	}
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"errors"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// Sink is a destination for log events. Monitors write each event with the
+// ID of the tab it belongs to, and close a tab's output for a site when the
+// tab leaves the site or closes.
+type Sink interface {
+	// WriteEvent writes an event for a tab.
+	WriteEvent(tabID string, event *events.LogEvent) error
+
+	// CloseTab finishes a tab's output for a site.
+	CloseTab(tabID, site string) error
+
+	// Close finishes all output.
+	Close() error
+}
+
+// FileManager writes each tab's events to JSONL files.
+var _ Sink = (*FileManager)(nil)
+
+// FanOutSink sends every event to several sinks, so events can go to files
+// and other tools at the same time.
+type FanOutSink struct {
+	sinks []Sink
+}
+
+// NewFanOutSink creates a sink that writes to all of sinks in order.
+func NewFanOutSink(sinks ...Sink) *FanOutSink {
+	return &FanOutSink{sinks: sinks}
+}
+
+// WriteEvent writes the event to every sink. A sink that fails doesn't stop
+// the others from receiving the event.
+func (s *FanOutSink) WriteEvent(tabID string, event *events.LogEvent) error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := sink.WriteEvent(tabID, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CloseTab closes the tab's output for a site in every sink.
+func (s *FanOutSink) CloseTab(tabID, site string) error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := sink.CloseTab(tabID, site); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every sink.
+func (s *FanOutSink) Close() error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// recordingSink records the calls it receives and fails with err.
+type recordingSink struct {
+	events []string
+	closed []string
+	done   bool
+	err    error
+}
+
+func (s *recordingSink) WriteEvent(tabID string, event *events.LogEvent) error {
+	s.events = append(s.events, tabID+":"+event.EventType)
+	return s.err
+}
+
+func (s *recordingSink) CloseTab(tabID, site string) error {
+	s.closed = append(s.closed, tabID+":"+site)
+	return s.err
+}
+
+func (s *recordingSink) Close() error {
+	s.done = true
+	return s.err
+}
+
+func TestFanOutSink(t *testing.T) {
+	failErr := errors.New("unavailable")
+	failing := &recordingSink{err: failErr}
+	working := &recordingSink{}
+	sink := NewFanOutSink(failing, working)
+
+	err := sink.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.load", nil))
+	if !errors.Is(err, failErr) {
+		t.Errorf("expected the failing sink's error, got %v", err)
+	}
+	if err := sink.CloseTab("tab-1", "example.com"); !errors.Is(err, failErr) {
+		t.Errorf("expected the failing sink's error from CloseTab, got %v", err)
+	}
+	if err := sink.Close(); !errors.Is(err, failErr) {
+		t.Errorf("expected the failing sink's error from Close, got %v", err)
+	}
+
+	// A failing sink doesn't stop the others
+	for _, s := range []*recordingSink{failing, working} {
+		if len(s.events) != 1 || s.events[0] != "tab-1:page.load" {
+			t.Errorf("expected the event in every sink, got %v", s.events)
+		}
+		if len(s.closed) != 1 || s.closed[0] != "tab-1:example.com" {
+			t.Errorf("expected the tab closed in every sink, got %v", s.closed)
+		}
+		if !s.done {
+			t.Error("expected every sink to be closed")
+		}
+	}
+}
+
+func TestFanOutSinkFiles(t *testing.T) {
+	dirA, dirB := t.TempDir(), t.TempDir()
+	sink := NewFanOutSink(NewFileManager(dirA), NewFileManager(dirB))
+
+	if err := sink.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.load", nil)); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	for _, dir := range []string{dirA, dirB} {
+		if got := eventTypes(t, GetLogPath(dir, "example.com", "tab-1")); len(got) != 1 || got[0] != "page.load" {
+			t.Errorf("expected the event in %s, got %v", dir, got)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// StreamSink writes every event as one JSON line to a single stream, such as
+// stdout. Events carry their site and tab ID, so lines from all tabs can be
+// interleaved.
+type StreamSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStreamSink creates a sink that writes JSON lines to w.
+func NewStreamSink(w io.Writer) *StreamSink {
+	return &StreamSink{w: w}
+}
+
+// WriteEvent writes the event as a single line. Lines are written unbuffered
+// so readers such as jq see each event as it happens.
+func (s *StreamSink) WriteEvent(tabID string, event *events.LogEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(data)
+	return err
+}
+
+// CloseTab does nothing: the stream is shared by all tabs.
+func (s *StreamSink) CloseTab(tabID, site string) error {
+	return nil
+}
+
+// Close does nothing: the stream belongs to the caller.
+func (s *StreamSink) Close() error {
+	return nil
+}
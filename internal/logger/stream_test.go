@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestStreamSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewStreamSink(&buf)
+
+	if err := sink.WriteEvent("tab-1", events.NewLogEvent("example.com", "tab-1", "page.load", nil)); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+	if err := sink.CloseTab("tab-1", "example.com"); err != nil {
+		t.Fatalf("CloseTab failed: %v", err)
+	}
+	if err := sink.WriteEvent("tab-2", events.NewLogEvent("github.com", "tab-2", "console.log", nil)); err != nil {
+		t.Fatalf("WriteEvent failed: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+
+	want := []struct{ site, tabID, eventType string }{
+		{"example.com", "tab-1", "page.load"},
+		{"github.com", "tab-2", "console.log"},
+	}
+	for i, line := range lines {
+		var ev events.LogEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if ev.Site != want[i].site || ev.TabID != want[i].tabID || ev.EventType != want[i].eventType {
+			t.Errorf("line %d: got %s/%s %s, want %v", i, ev.Site, ev.TabID, ev.EventType, want[i])
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/chromedp/cdproto/audits"
+	"github.com/chromedp/chromedp"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+	"github.com/ajsharma/browser_tail/internal/redact"
+)
+
+// enableAudits enables the Audits domain so Chrome reports inspector issues.
+func enableAudits(targetCtx context.Context) error {
+	return chromedp.Run(targetCtx, audits.Enable())
+}
+
+// handleIssueAdded logs an inspector issue reported by the Audits domain.
+func (tm *TabMonitor) handleIssueAdded(issue *audits.InspectorIssue, site, tabID string) {
+	if issue == nil {
+		return
+	}
+
+	details := issueDetails(issue.Details)
+	if tm.redactor.IsEnabled() {
+		// Cookie issues carry the raw Set-Cookie line, including its value
+		if _, ok := details["rawCookieLine"]; ok {
+			details["rawCookieLine"] = redact.RedactedValue
+		}
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventAuditIssue, &events.AuditIssueData{
+		Code:      issue.Code.String(),
+		IssueID:   issue.IssueID.String(),
+		RequestID: issueRequestID(details),
+		Details:   details,
+	}))
+}
+
+// issueDetails flattens the issue details union into the single populated
+// details object. Returns an empty map if no details are set.
+func issueDetails(d *audits.InspectorIssueDetails) map[string]interface{} {
+	result := make(map[string]interface{})
+	if d == nil {
+		return result
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return result
+	}
+
+	var union map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &union); err != nil {
+		return result
+	}
+
+	// Exactly one member of the union is populated per issue
+	for _, details := range union {
+		if details != nil {
+			return details
+		}
+	}
+	return result
+}
+
+// issueRequestID returns the network request an issue refers to, if any.
+func issueRequestID(details map[string]interface{}) string {
+	if id, ok := details["requestId"].(string); ok {
+		return id
+	}
+	if req, ok := details["request"].(map[string]interface{}); ok {
+		if id, ok := req["requestId"].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
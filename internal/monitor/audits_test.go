@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/audits"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+	"github.com/ajsharma/browser_tail/internal/redact"
+)
+
+func TestAuditIssueAdded(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableAudits = true
+	tm, dir := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&audits.EventIssueAdded{Issue: &audits.InspectorIssue{
+		Code:    audits.InspectorIssueCodeCookieIssue,
+		IssueID: "issue-1",
+		Details: &audits.InspectorIssueDetails{
+			CookieIssueDetails: &audits.CookieIssueDetails{
+				RawCookieLine: "session=abc123; SameSite=None",
+				CookieURL:     "https://example.com/",
+				Request:       &audits.AffectedRequest{RequestID: "req-1", URL: "https://example.com/"},
+			},
+		},
+	}})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(got))
+	}
+	if got[0]["event_type"] != events.EventAuditIssue {
+		t.Errorf("expected %s, got %v", events.EventAuditIssue, got[0]["event_type"])
+	}
+
+	data := got[0]["data"].(map[string]interface{})
+	if data["code"] != "CookieIssue" {
+		t.Errorf("expected code CookieIssue, got %v", data["code"])
+	}
+	if data["request_id"] != "req-1" {
+		t.Errorf("expected request_id req-1, got %v", data["request_id"])
+	}
+
+	details := data["details"].(map[string]interface{})
+	if details["cookieUrl"] != "https://example.com/" {
+		t.Errorf("expected flattened cookie details, got %v", details)
+	}
+	if details["rawCookieLine"] != redact.RedactedValue {
+		t.Errorf("expected raw cookie line to be redacted, got %v", details["rawCookieLine"])
+	}
+}
+
+func TestAuditIssueDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableAudits = false
+	tm, _ := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&audits.EventIssueAdded{Issue: &audits.InspectorIssue{Code: audits.InspectorIssueCodeCorsIssue}})
+
+	if openTestFiles(tm) != 0 {
+		t.Error("expected no events to be written when audits are disabled")
+	}
+}
+
+func TestIssueRequestID(t *testing.T) {
+	tests := []struct {
+		name    string
+		details map[string]interface{}
+		want    string
+	}{
+		{"affected request", map[string]interface{}{"request": map[string]interface{}{"requestId": "r1"}}, "r1"},
+		{"top-level request id", map[string]interface{}{"requestId": "r2"}, "r2"},
+		{"no request", map[string]interface{}{"violatingNodeId": float64(3)}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issueRequestID(tt.details); got != tt.want {
+				t.Errorf("issueRequestID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"context"
+
+	cdplog "github.com/chromedp/cdproto/log"
+	"github.com/chromedp/chromedp"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// violationSettings mirrors the thresholds DevTools uses for "[Violation]"
+// messages. A threshold of -1 reports every occurrence.
+var violationSettings = []*cdplog.ViolationSetting{
+	{Name: cdplog.ViolationLongTask, Threshold: 200},
+	{Name: cdplog.ViolationLongLayout, Threshold: 30},
+	{Name: cdplog.ViolationBlockedEvent, Threshold: 100},
+	{Name: cdplog.ViolationBlockedParser, Threshold: -1},
+	{Name: cdplog.ViolationHandler, Threshold: 150},
+	{Name: cdplog.ViolationRecurringHandler, Threshold: 50},
+	{Name: cdplog.ViolationDiscouragedAPIUse, Threshold: -1},
+}
+
+// enableBrowserLog enables the Log domain and violation reporting on a target.
+func enableBrowserLog(targetCtx context.Context) error {
+	return chromedp.Run(targetCtx,
+		cdplog.Enable(),
+		cdplog.StartViolationsReport(violationSettings),
+	)
+}
+
+// handleLogEntry logs a browser-generated message from the Log domain.
+// Verbose messages, which DevTools only shows at the console's Verbose
+// level, are logged as console.verbose.
+func (tm *TabMonitor) handleLogEntry(entry *cdplog.Entry, site, tabID string) {
+	if entry == nil {
+		return
+	}
+
+	eventType := events.EventLogEntry
+	if entry.Level == cdplog.LevelVerbose {
+		eventType = events.EventConsoleVerbose
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, eventType, &events.LogEntryData{
+		Source:    entry.Source.String(),
+		Level:     logLevelSeverity(entry.Level),
+		Category:  entry.Category.String(),
+		Text:      entry.Text,
+		URL:       entry.URL,
+		Line:      entry.LineNumber,
+		RequestID: entry.NetworkRequestID.String(),
+	}))
+}
+
+// logLevelSeverity maps a Log domain level onto the severity names used by
+// console.* events.
+func logLevelSeverity(level cdplog.Level) string {
+	switch level {
+	case cdplog.LevelVerbose:
+		return "verbose"
+	case cdplog.LevelWarning:
+		return "warn"
+	case cdplog.LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"testing"
+
+	cdplog "github.com/chromedp/cdproto/log"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestLogLevelSeverity(t *testing.T) {
+	tests := []struct {
+		level cdplog.Level
+		want  string
+	}{
+		{cdplog.LevelVerbose, "verbose"},
+		{cdplog.LevelInfo, "info"},
+		{cdplog.LevelWarning, "warn"},
+		{cdplog.LevelError, "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			if got := logLevelSeverity(tt.level); got != tt.want {
+				t.Errorf("logLevelSeverity(%s) = %s, want %s", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleLogEntry(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableBrowserLog = true
+	tm, dir := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&cdplog.EventEntryAdded{Entry: &cdplog.Entry{
+		Source:           cdplog.SourceSecurity,
+		Level:            cdplog.LevelError,
+		Text:             "Refused to load the script because it violates the Content Security Policy",
+		URL:              "https://example.com/",
+		LineNumber:       12,
+		NetworkRequestID: "req-7",
+	}})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(got))
+	}
+	if got[0]["event_type"] != events.EventLogEntry {
+		t.Fatalf("expected %s, got %v", events.EventLogEntry, got[0]["event_type"])
+	}
+
+	data := got[0]["data"].(map[string]interface{})
+	if data["source"] != "security" || data["level"] != "error" {
+		t.Errorf("unexpected source/level: %v", data)
+	}
+	if data["line"] != float64(12) || data["request_id"] != "req-7" {
+		t.Errorf("unexpected line/request_id: %v", data)
+	}
+}
+
+func TestHandleLogEntryVerbose(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableBrowserLog = true
+	tm, dir := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&cdplog.EventEntryAdded{Entry: &cdplog.Entry{
+		Source: cdplog.SourceViolation,
+		Level:  cdplog.LevelVerbose,
+		Text:   "[Violation] 'setTimeout' handler took 180ms",
+	}})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(got))
+	}
+	if got[0]["event_type"] != events.EventConsoleVerbose {
+		t.Fatalf("expected %s, got %v", events.EventConsoleVerbose, got[0]["event_type"])
+	}
+	if data := got[0]["data"].(map[string]interface{}); data["source"] != "violation" || data["level"] != "verbose" {
+		t.Errorf("unexpected source/level: %v", data)
+	}
+}
+
+func TestHandleLogEntryDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableBrowserLog = false
+	tm, _ := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&cdplog.EventEntryAdded{Entry: &cdplog.Entry{Level: cdplog.LevelError, Text: "ignored"}})
+
+	if openTestFiles(tm) != 0 {
+		t.Error("expected no events to be written when browser log is disabled")
+	}
+}
@@ -0,0 +1,180 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// eventOrigin identifies the non-page target a monitor's events come from.
+type eventOrigin struct {
+	WorkerID   string
+	WorkerType string
+	FrameID    string
+}
+
+// isChild reports whether the origin refers to a worker or frame target
+// rather than a top-level tab.
+func (o eventOrigin) isChild() bool {
+	return o.WorkerID != "" || o.FrameID != ""
+}
+
+// handleAttachedToTarget starts monitoring workers and out-of-process
+// iframes that Chrome auto-attached to this target.
+func (tm *TabMonitor) handleAttachedToTarget(sessionID target.SessionID, info *target.Info) {
+	if info == nil {
+		return
+	}
+
+	switch {
+	case info.Type == TargetTypeWorker && tm.config.EnableWorkers:
+		tm.attachChild(sessionID, info, eventOrigin{
+			WorkerID:   string(info.TargetID),
+			WorkerType: info.Type,
+		})
+	case info.Type == TargetTypeIframe && tm.config.EnableFrames:
+		tm.attachChild(sessionID, info, eventOrigin{
+			FrameID: string(info.TargetID),
+		})
+	}
+}
+
+// attachChild starts monitoring a target owned by this tab.
+// Its events are written to this tab's log, tagged with origin.
+func (tm *TabMonitor) attachChild(sessionID target.SessionID, info *target.Info, origin eventOrigin) {
+	tm.mu.RLock()
+	browserCtx := tm.browserCtx
+	sourceMaps := tm.sourceMaps
+	tm.mu.RUnlock()
+
+	if browserCtx == nil {
+		return
+	}
+
+	tm.childMu.Lock()
+	if _, exists := tm.children[sessionID]; exists {
+		tm.childMu.Unlock()
+		return
+	}
+	child := NewTabMonitor(tm.ctx, string(info.TargetID), tm.tabID, "", "", info.URL, tm.sessionID, tm.sink, tm.config)
+	child.parent = tm
+	child.origin = origin
+	child.sourceMaps = sourceMaps
+	tm.children[sessionID] = child
+	tm.childMu.Unlock()
+
+	// Attach in a goroutine: CDP commands cannot be run from an event listener
+	go func() {
+		if err := child.Start(browserCtx); err != nil {
+			log.Printf("Warning: failed to monitor %s target (tab %s, target %s): %v",
+				info.Type, tm.tabID, child.targetID, err)
+		}
+		tm.detachChild(sessionID)
+	}()
+}
+
+// detachChild stops monitoring the child target attached with the given session.
+func (tm *TabMonitor) detachChild(sessionID target.SessionID) {
+	tm.childMu.Lock()
+	child, exists := tm.children[sessionID]
+	delete(tm.children, sessionID)
+	tm.childMu.Unlock()
+
+	if exists {
+		child.Stop()
+	}
+}
+
+// stopChildren stops all child monitors.
+func (tm *TabMonitor) stopChildren() {
+	tm.childMu.Lock()
+	children := make([]*TabMonitor, 0, len(tm.children))
+	for _, child := range tm.children {
+		children = append(children, child)
+	}
+	tm.children = make(map[target.SessionID]*TabMonitor)
+	tm.childMu.Unlock()
+
+	for _, child := range children {
+		child.Stop()
+	}
+}
+
+// startChild attaches to a worker or frame target and monitors it until the
+// monitor is stopped or the target goes away.
+func (tm *TabMonitor) startChild(browserCtx context.Context) error {
+	targetCtx, cancel := chromedp.NewContext(browserCtx,
+		chromedp.WithTargetID(target.ID(tm.targetID)),
+	)
+	defer cancel()
+
+	tm.mu.Lock()
+	tm.targetCtx = targetCtx
+	tm.browserCtx = browserCtx
+	tm.mu.Unlock()
+
+	// Attaching enables the Runtime, Network and Log domains on all targets,
+	// plus Page and target auto-attach on frames
+	if err := chromedp.Run(targetCtx); err != nil {
+		return err
+	}
+
+	if tm.config.EnableSourceMaps && tm.sourceMapCache() != nil {
+		if err := enableDebugger(targetCtx); err != nil {
+			log.Printf("Warning: failed to enable source maps (tab %s, target %s): %v", tm.tabID, tm.targetID, err)
+		}
+	}
+
+	if tm.origin.WorkerID != "" {
+		site, tabID := tm.siteAndTab()
+		tm.writeEvent(events.NewWorkerAttachedEvent(site, tabID, tm.origin.WorkerID, tm.origin.WorkerType, tm.CurrentURL()))
+	}
+
+	tm.mu.Lock()
+	tm.attached = true
+	tm.mu.Unlock()
+
+	chromedp.ListenTarget(targetCtx, func(ev interface{}) {
+		tm.handleEvent(ev)
+	})
+
+	go tm.runRequestCleanup(targetCtx)
+
+	select {
+	case <-tm.ctx.Done():
+	case <-targetCtx.Done():
+	}
+
+	return nil
+}
+
+// stopChild writes a worker's detached event and releases the log file.
+// Child targets share their tab's log, so only standalone workers close it.
+func (tm *TabMonitor) stopChild() {
+	site, tabID := tm.siteAndTab()
+
+	tm.mu.RLock()
+	attached := tm.attached
+	duration := time.Since(tm.startTime).Seconds()
+	tm.mu.RUnlock()
+
+	if attached && tm.origin.WorkerID != "" {
+		tm.writeEvent(events.NewWorkerDetachedEvent(site, tabID, tm.origin.WorkerID, tm.origin.WorkerType, duration))
+	}
+
+	if tm.parent == nil {
+		tm.output(func() {
+			if err := tm.sink.CloseTab(tabID, site); err != nil {
+				log.Printf("Warning: failed to close worker log (worker %s): %v", tm.origin.WorkerID, err)
+			}
+		})
+		tm.closeOutput()
+	}
+
+	tm.cancel()
+}
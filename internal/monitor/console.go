@@ -0,0 +1,190 @@
+package monitor
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/runtime"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// tableIndexColumn names the row key column of console.table rows, as in DevTools.
+const tableIndexColumn = "(index)"
+
+// consoleEventTypes maps console API calls to event types. Calls not listed
+// here (console.log and anything new) are logged as console.log.
+var consoleEventTypes = map[runtime.APIType]string{
+	runtime.APITypeWarning:             events.EventConsoleWarn,
+	runtime.APITypeError:               events.EventConsoleError,
+	runtime.APITypeInfo:                events.EventConsoleInfo,
+	runtime.APITypeDebug:               events.EventConsoleDebug,
+	runtime.APITypeTable:               events.EventConsoleTable,
+	runtime.APITypeDir:                 events.EventConsoleDir,
+	runtime.APITypeDirxml:              events.EventConsoleDirXML,
+	runtime.APITypeTrace:               events.EventConsoleTrace,
+	runtime.APITypeStartGroup:          events.EventConsoleGroup,
+	runtime.APITypeStartGroupCollapsed: events.EventConsoleGroupCollapsed,
+	runtime.APITypeEndGroup:            events.EventConsoleGroupEnd,
+	runtime.APITypeAssert:              events.EventConsoleAssert,
+	runtime.APITypeCount:               events.EventConsoleCount,
+	runtime.APITypeTimeEnd:             events.EventConsoleTimeEnd,
+	runtime.APITypeClear:               events.EventConsoleClear,
+	runtime.APITypeProfile:             events.EventConsoleProfile,
+	runtime.APITypeProfileEnd:          events.EventConsoleProfileEnd,
+}
+
+// handleConsoleAPICalled logs a console API call. Messages inside
+// console.group blocks carry the group nesting depth.
+func (tm *TabMonitor) handleConsoleAPICalled(ev *runtime.EventConsoleAPICalled, site, tabID string) {
+	eventType, ok := consoleEventTypes[ev.Type]
+	if !ok {
+		eventType = events.EventConsoleLog
+	}
+
+	args := make([]interface{}, 0, len(ev.Args))
+	for _, arg := range ev.Args {
+		args = append(args, extractRemoteObjectValue(arg))
+	}
+
+	data := &events.ConsoleData{
+		Args:       args,
+		Stack:      convertStackTrace(ev.StackTrace),
+		GroupDepth: tm.consoleGroupDepth(ev.Type),
+	}
+
+	switch ev.Type {
+	case runtime.APITypeStartGroup, runtime.APITypeStartGroupCollapsed:
+		if len(args) > 0 {
+			data.Label, _ = args[0].(string)
+		}
+	case runtime.APITypeCount:
+		// Chrome reports console.count("x") as the message "x: 3"
+		label, value := splitConsoleLabel(args)
+		if n, err := strconv.Atoi(value); err == nil {
+			data.Label = label
+			data.Count = n
+		}
+	case runtime.APITypeTimeEnd:
+		// ... and console.timeEnd("x") as "x: 12.3 ms"
+		label, value := splitConsoleLabel(args)
+		if ms, ok := parseConsoleDuration(value); ok {
+			data.Label = label
+			data.DurationMs = ms
+		}
+	case runtime.APITypeTable:
+		data.Rows, data.Columns = tableRows(ev.Args)
+	}
+
+	tm.writeSymbolicated(events.NewLogEvent(site, tabID, eventType, data))
+}
+
+// consoleGroupDepth returns the group nesting depth of a console message and
+// tracks groups opening and closing. A group's start and end messages are at
+// the depth of the enclosing group.
+func (tm *TabMonitor) consoleGroupDepth(apiType runtime.APIType) int {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	switch apiType {
+	case runtime.APITypeStartGroup, runtime.APITypeStartGroupCollapsed:
+		tm.consoleDepth++
+		return tm.consoleDepth - 1
+	case runtime.APITypeEndGroup:
+		if tm.consoleDepth > 0 {
+			tm.consoleDepth--
+		}
+	}
+	return tm.consoleDepth
+}
+
+// resetConsoleGroups forgets open console groups, e.g. after navigation.
+func (tm *TabMonitor) resetConsoleGroups() {
+	tm.mu.Lock()
+	tm.consoleDepth = 0
+	tm.mu.Unlock()
+}
+
+// splitConsoleLabel splits a "label: value" console message.
+func splitConsoleLabel(args []interface{}) (string, string) {
+	if len(args) == 0 {
+		return "", ""
+	}
+	msg, ok := args[0].(string)
+	if !ok {
+		return "", ""
+	}
+	i := strings.LastIndex(msg, ": ")
+	if i < 0 {
+		return "", ""
+	}
+	return msg[:i], msg[i+2:]
+}
+
+// parseConsoleDuration parses a console.timeEnd duration such as "12.3 ms"
+// or "1.5 s" into milliseconds.
+func parseConsoleDuration(s string) (float64, bool) {
+	value, unit, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch unit {
+	case "ms":
+		return roundMs(v), true
+	case "s":
+		return roundMs(v * 1000), true
+	case "min":
+		return roundMs(v * 60 * 1000), true
+	}
+	return 0, false
+}
+
+// tableRows renders the data passed to console.table as rows keyed by
+// column name, using the object preview Chrome attaches to the call. Each row
+// includes its key under tableIndexColumn; primitive rows have a single
+// "Value" column. If console.table was given a column list, only those
+// columns are kept and returned.
+func tableRows(args []*runtime.RemoteObject) ([]map[string]interface{}, []string) {
+	if len(args) == 0 || args[0] == nil || args[0].Preview == nil {
+		return nil, nil
+	}
+
+	var columns []string
+	if len(args) > 1 {
+		if list, ok := extractRemoteObjectValue(args[1]).([]interface{}); ok {
+			for _, c := range list {
+				if name, ok := c.(string); ok {
+					columns = append(columns, name)
+				}
+			}
+		}
+	}
+
+	rows := make([]map[string]interface{}, 0, len(args[0].Preview.Properties))
+	for _, prop := range args[0].Preview.Properties {
+		row := map[string]interface{}{tableIndexColumn: prop.Name}
+		if prop.ValuePreview != nil {
+			for _, cell := range prop.ValuePreview.Properties {
+				row[cell.Name] = extractPropertyValue(cell)
+			}
+		} else {
+			row["Value"] = extractPropertyValue(prop)
+		}
+
+		if len(columns) > 0 {
+			filtered := map[string]interface{}{tableIndexColumn: prop.Name}
+			for _, c := range columns {
+				if v, ok := row[c]; ok {
+					filtered[c] = v
+				}
+			}
+			row = filtered
+		}
+		rows = append(rows, row)
+	}
+	return rows, columns
+}
@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/runtime"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// consoleString returns a string console argument.
+func consoleString(s string) *runtime.RemoteObject {
+	return &runtime.RemoteObject{Type: runtime.TypeString, Value: []byte(`"` + s + `"`)}
+}
+
+func TestConsoleEventTypesAndGroupDepth(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	calls := []struct {
+		apiType runtime.APIType
+		args    []*runtime.RemoteObject
+	}{
+		{runtime.APITypeLog, []*runtime.RemoteObject{consoleString("before")}},
+		{runtime.APITypeStartGroup, []*runtime.RemoteObject{consoleString("checkout")}},
+		{runtime.APITypeLog, []*runtime.RemoteObject{consoleString("inside")}},
+		{runtime.APITypeStartGroupCollapsed, []*runtime.RemoteObject{consoleString("payment")}},
+		{runtime.APITypeCount, []*runtime.RemoteObject{consoleString("retries: 3")}},
+		{runtime.APITypeTimeEnd, []*runtime.RemoteObject{consoleString("charge: 12.5 ms")}},
+		{runtime.APITypeEndGroup, nil},
+		{runtime.APITypeAssert, []*runtime.RemoteObject{consoleString("total mismatch")}},
+		{runtime.APITypeEndGroup, nil},
+		{runtime.APITypeTrace, []*runtime.RemoteObject{consoleString("after")}},
+	}
+	for _, c := range calls {
+		tm.handleEvent(&runtime.EventConsoleAPICalled{Type: c.apiType, Args: c.args})
+	}
+
+	got := readTestEvents(t, tm, dir)
+	want := []struct {
+		eventType string
+		depth     float64
+	}{
+		{events.EventConsoleLog, 0},
+		{events.EventConsoleGroup, 0},
+		{events.EventConsoleLog, 1},
+		{events.EventConsoleGroupCollapsed, 1},
+		{events.EventConsoleCount, 2},
+		{events.EventConsoleTimeEnd, 2},
+		{events.EventConsoleGroupEnd, 1},
+		{events.EventConsoleAssert, 1},
+		{events.EventConsoleGroupEnd, 0},
+		{events.EventConsoleTrace, 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		data := got[i]["data"].(map[string]interface{})
+		depth, _ := data["group_depth"].(float64)
+		if got[i]["event_type"] != w.eventType || depth != w.depth {
+			t.Errorf("event %d: got %v depth %v, want %s depth %v", i, got[i]["event_type"], depth, w.eventType, w.depth)
+		}
+	}
+
+	if group := got[1]["data"].(map[string]interface{}); group["label"] != "checkout" {
+		t.Errorf("expected group label, got %v", group["label"])
+	}
+	if count := got[4]["data"].(map[string]interface{}); count["label"] != "retries" || count["count"] != float64(3) {
+		t.Errorf("unexpected count data: %v", count)
+	}
+	if timer := got[5]["data"].(map[string]interface{}); timer["label"] != "charge" || timer["duration_ms"] != 12.5 {
+		t.Errorf("unexpected time_end data: %v", timer)
+	}
+}
+
+func TestConsoleGroupsResetOnNavigation(t *testing.T) {
+	tm, _ := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&runtime.EventConsoleAPICalled{Type: runtime.APITypeStartGroup})
+	tm.handleEvent(&runtime.EventExecutionContextsCleared{})
+
+	if tm.consoleDepth != 0 {
+		t.Errorf("expected group depth to reset, got %d", tm.consoleDepth)
+	}
+}
+
+func TestConsoleTableRows(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	data := &runtime.RemoteObject{
+		Type:    runtime.TypeObject,
+		Subtype: runtime.SubtypeArray,
+		Preview: &runtime.ObjectPreview{
+			Type:    runtime.TypeObject,
+			Subtype: runtime.SubtypeArray,
+			Properties: []*runtime.PropertyPreview{
+				{Name: "0", Type: runtime.TypeObject, ValuePreview: &runtime.ObjectPreview{Properties: []*runtime.PropertyPreview{
+					{Name: "sku", Type: runtime.TypeString, Value: "A-1"},
+					{Name: "qty", Type: runtime.TypeNumber, Value: "2"},
+				}}},
+				{Name: "1", Type: runtime.TypeObject, ValuePreview: &runtime.ObjectPreview{Properties: []*runtime.PropertyPreview{
+					{Name: "sku", Type: runtime.TypeString, Value: "B-7"},
+					{Name: "qty", Type: runtime.TypeNumber, Value: "1"},
+				}}},
+				{Name: "2", Type: runtime.TypeString, Value: "loose"},
+			},
+		},
+	}
+	columns := &runtime.RemoteObject{Type: runtime.TypeObject, Subtype: runtime.SubtypeArray, Value: []byte(`["sku"]`)}
+
+	tm.handleEvent(&runtime.EventConsoleAPICalled{Type: runtime.APITypeTable, Args: []*runtime.RemoteObject{data}})
+	tm.handleEvent(&runtime.EventConsoleAPICalled{Type: runtime.APITypeTable, Args: []*runtime.RemoteObject{data, columns}})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 2 || got[0]["event_type"] != events.EventConsoleTable {
+		t.Fatalf("expected 2 %s events, got %v", events.EventConsoleTable, got)
+	}
+
+	rows := got[0]["data"].(map[string]interface{})["rows"].([]interface{})
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	first := rows[0].(map[string]interface{})
+	if first[tableIndexColumn] != "0" || first["sku"] != "A-1" || first["qty"] != float64(2) {
+		t.Errorf("unexpected first row: %v", first)
+	}
+	if loose := rows[2].(map[string]interface{}); loose["Value"] != "loose" {
+		t.Errorf("expected primitive row under Value, got %v", loose)
+	}
+
+	filtered := got[1]["data"].(map[string]interface{})
+	row := filtered["rows"].([]interface{})[1].(map[string]interface{})
+	if _, ok := row["qty"]; ok || row["sku"] != "B-7" {
+		t.Errorf("expected only the requested columns, got %v", row)
+	}
+	if cols := filtered["columns"].([]interface{}); len(cols) != 1 || cols[0] != "sku" {
+		t.Errorf("unexpected columns: %v", cols)
+	}
+}
+
+func TestParseConsoleDuration(t *testing.T) {
+	tests := map[string]float64{
+		"12.5 ms": 12.5,
+		"1.5 s":   1500,
+		"2 min":   120000,
+	}
+	for input, want := range tests {
+		if got, ok := parseConsoleDuration(input); !ok || got != want {
+			t.Errorf("parseConsoleDuration(%q) = %v, %v; want %v", input, got, ok, want)
+		}
+	}
+	if _, ok := parseConsoleDuration("soon"); ok {
+		t.Error("expected unparseable duration to be rejected")
+	}
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// Dialog handlers reported in page.dialog_closed events.
+const (
+	DialogHandlerUser   = "user"
+	DialogHandlerPolicy = "policy"
+)
+
+// openDialog is the JavaScript dialog currently blocking a target.
+// Chrome shows at most one dialog per page at a time.
+type openDialog struct {
+	Type     string
+	Handler  string
+	OpenedAt time.Time
+}
+
+// handleDialogOpening logs a JavaScript dialog and, if the dialog policy
+// says so, answers it so the page can continue.
+func (tm *TabMonitor) handleDialogOpening(ev *page.EventJavascriptDialogOpening, site, tabID string) {
+	policy := tm.config.DialogPolicy
+	if policy != config.DialogPolicyAccept && policy != config.DialogPolicyDismiss {
+		policy = ""
+	}
+
+	handler := DialogHandlerUser
+	if policy != "" {
+		handler = DialogHandlerPolicy
+	}
+
+	tm.mu.Lock()
+	tm.dialog = &openDialog{Type: ev.Type.String(), Handler: handler, OpenedAt: time.Now()}
+	tm.mu.Unlock()
+
+	if tm.config.EnablePage {
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPageDialogOpened, &events.DialogOpenedData{
+			Type:          ev.Type.String(),
+			Message:       ev.Message,
+			URL:           ev.URL,
+			FrameID:       ev.FrameID.String(),
+			DefaultPrompt: ev.DefaultPrompt,
+			Policy:        policy,
+		}))
+	}
+
+	if policy != "" {
+		// Answer in a goroutine: CDP commands cannot be run from an event listener
+		go tm.answerDialog(policy == config.DialogPolicyAccept, ev.DefaultPrompt)
+	}
+}
+
+// answerDialog accepts or dismisses the open dialog. Prompts are accepted
+// with their default text.
+func (tm *TabMonitor) answerDialog(accept bool, promptText string) {
+	tm.mu.RLock()
+	tCtx := tm.targetCtx
+	tm.mu.RUnlock()
+
+	if tCtx == nil {
+		return
+	}
+
+	err := chromedp.Run(tCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		return page.HandleJavaScriptDialog(accept).WithPromptText(promptText).Do(ctx)
+	}))
+	if err != nil {
+		log.Printf("Warning: failed to answer dialog (tab %s): %v", tm.tabID, err)
+	}
+}
+
+// handleDialogClosed logs how the open dialog was answered.
+func (tm *TabMonitor) handleDialogClosed(ev *page.EventJavascriptDialogClosed, site, tabID string) {
+	tm.mu.Lock()
+	dialog := tm.dialog
+	tm.dialog = nil
+	tm.mu.Unlock()
+
+	if !tm.config.EnablePage {
+		return
+	}
+
+	data := &events.DialogClosedData{
+		FrameID:   ev.FrameID.String(),
+		Accepted:  ev.Result,
+		UserInput: tm.redactor.RedactBody(ev.UserInput),
+	}
+	if dialog != nil {
+		data.Type = dialog.Type
+		data.Handler = dialog.Handler
+		data.DurationMs = roundMs(time.Since(dialog.OpenedAt).Seconds() * 1000)
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPageDialogClosed, data))
+}
@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/page"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestDialogOpenedAndClosed(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&page.EventJavascriptDialogOpening{
+		URL:           "https://example.com/",
+		FrameID:       "target-1",
+		Message:       "Your name?",
+		Type:          page.DialogTypePrompt,
+		DefaultPrompt: "guest",
+	})
+	tm.handleEvent(&page.EventJavascriptDialogClosed{FrameID: "target-1", Result: true, UserInput: "ada"})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	if got[0]["event_type"] != events.EventPageDialogOpened || got[1]["event_type"] != events.EventPageDialogClosed {
+		t.Fatalf("unexpected event types: %v, %v", got[0]["event_type"], got[1]["event_type"])
+	}
+
+	opened := got[0]["data"].(map[string]interface{})
+	if opened["type"] != "prompt" || opened["message"] != "Your name?" || opened["default_prompt"] != "guest" {
+		t.Errorf("unexpected dialog_opened data: %v", opened)
+	}
+	if _, ok := opened["policy"]; ok {
+		t.Errorf("expected no policy for manual dialogs, got %v", opened["policy"])
+	}
+
+	closed := got[1]["data"].(map[string]interface{})
+	if closed["type"] != "prompt" || closed["accepted"] != true || closed["user_input"] != "ada" {
+		t.Errorf("unexpected dialog_closed data: %v", closed)
+	}
+	if closed["handler"] != DialogHandlerUser {
+		t.Errorf("expected handler %s, got %v", DialogHandlerUser, closed["handler"])
+	}
+	if tm.dialog != nil {
+		t.Error("expected open dialog to be cleared")
+	}
+}
+
+func TestDialogPolicy(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DialogPolicy = config.DialogPolicyDismiss
+	tm, dir := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&page.EventJavascriptDialogOpening{Message: "Leave site?", Type: page.DialogTypeBeforeunload})
+	tm.handleEvent(&page.EventJavascriptDialogClosed{Result: false})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	if opened := got[0]["data"].(map[string]interface{}); opened["policy"] != config.DialogPolicyDismiss {
+		t.Errorf("expected policy %s, got %v", config.DialogPolicyDismiss, opened["policy"])
+	}
+	closed := got[1]["data"].(map[string]interface{})
+	if closed["handler"] != DialogHandlerPolicy || closed["accepted"] != false {
+		t.Errorf("unexpected dialog_closed data: %v", closed)
+	}
+}
+
+func TestDialogEventsDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnablePage = false
+	tm, _ := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&page.EventJavascriptDialogOpening{Message: "hi", Type: page.DialogTypeAlert})
+	tm.handleEvent(&page.EventJavascriptDialogClosed{Result: true})
+
+	if openTestFiles(tm) != 0 {
+		t.Error("expected no events to be written when page events are disabled")
+	}
+}
@@ -0,0 +1,146 @@
+package monitor
+
+import (
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/page"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+const (
+	// downloadProgressInterval limits page.download_progress events per download.
+	downloadProgressInterval = time.Second
+
+	// maxDownloadURLLength caps data: URLs, which embed the whole file.
+	maxDownloadURLLength = 256
+)
+
+// downloadInfo tracks a download started from this tab.
+type downloadInfo struct {
+	URL          string
+	Filename     string
+	StartedAt    time.Time
+	LastProgress time.Time
+}
+
+// trackFrame records same-process child frames so browser-level events that
+// only carry a frame ID can be attributed to this tab.
+func (tm *TabMonitor) trackFrame(ev interface{}) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *page.EventFrameAttached:
+		tm.frames[ev.FrameID.String()] = struct{}{}
+	case *page.EventFrameDetached:
+		delete(tm.frames, ev.FrameID.String())
+	}
+}
+
+// OwnsFrame reports whether a frame belongs to this tab: its main frame, a
+// same-process child frame, or an out-of-process iframe attached to it.
+func (tm *TabMonitor) OwnsFrame(frameID string) bool {
+	tm.mu.RLock()
+	_, child := tm.frames[frameID]
+	owned := child || frameID == tm.targetID || frameID == tm.mainFrameID
+	tm.mu.RUnlock()
+	if owned {
+		return true
+	}
+
+	tm.childMu.Lock()
+	children := make([]*TabMonitor, 0, len(tm.children))
+	for _, c := range tm.children {
+		children = append(children, c)
+	}
+	tm.childMu.Unlock()
+
+	for _, c := range children {
+		if c.origin.FrameID != "" && c.OwnsFrame(frameID) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleDownloadWillBegin logs a download started from this tab.
+func (tm *TabMonitor) HandleDownloadWillBegin(ev *browser.EventDownloadWillBegin) {
+	site, tabID := tm.siteAndTab()
+	url := downloadURL(ev.URL)
+
+	tm.downloadMu.Lock()
+	tm.downloads[ev.GUID] = &downloadInfo{
+		URL:       url,
+		Filename:  ev.SuggestedFilename,
+		StartedAt: time.Now(),
+	}
+	tm.downloadMu.Unlock()
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPageDownloadStarted, &events.DownloadStartedData{
+		GUID:              ev.GUID,
+		URL:               url,
+		SuggestedFilename: ev.SuggestedFilename,
+		FrameID:           ev.FrameID.String(),
+	}))
+}
+
+// HandleDownloadProgress logs progress of a download started from this tab,
+// at most once per downloadProgressInterval, and its final state.
+// It reports whether the download has finished.
+func (tm *TabMonitor) HandleDownloadProgress(ev *browser.EventDownloadProgress) bool {
+	site, tabID := tm.siteAndTab()
+	done := ev.State != browser.DownloadProgressStateInProgress
+
+	tm.downloadMu.Lock()
+	info, exists := tm.downloads[ev.GUID]
+	if !exists {
+		info = &downloadInfo{StartedAt: time.Now()}
+		tm.downloads[ev.GUID] = info
+	}
+	report := done || time.Since(info.LastProgress) >= downloadProgressInterval
+	if report {
+		info.LastProgress = time.Now()
+	}
+	if done {
+		delete(tm.downloads, ev.GUID)
+	}
+	tm.downloadMu.Unlock()
+
+	if !done {
+		if report {
+			tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPageDownloadProgress, &events.DownloadProgressData{
+				GUID:          ev.GUID,
+				ReceivedBytes: ev.ReceivedBytes,
+				TotalBytes:    ev.TotalBytes,
+			}))
+		}
+		return false
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPageDownloadCompleted, &events.DownloadCompletedData{
+		GUID:              ev.GUID,
+		URL:               info.URL,
+		SuggestedFilename: info.Filename,
+		State:             ev.State.String(),
+		ReceivedBytes:     ev.ReceivedBytes,
+		TotalBytes:        ev.TotalBytes,
+		FilePath:          ev.FilePath,
+		DurationMs:        roundMs(time.Since(info.StartedAt).Seconds() * 1000),
+	}))
+	return true
+}
+
+// downloadURL shortens data: URLs, which would otherwise copy the
+// downloaded file into the log.
+func downloadURL(url string) string {
+	if !strings.HasPrefix(url, "data:") {
+		return url
+	}
+	if truncated, ok := truncatePayload(url, maxDownloadURLLength); ok {
+		return truncated + "..."
+	}
+	return url
+}
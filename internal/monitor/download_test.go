@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/page"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestDownloadLifecycle(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	tm.HandleDownloadWillBegin(&browser.EventDownloadWillBegin{
+		FrameID:           "target-1",
+		GUID:              "guid-1",
+		URL:               "https://example.com/export.csv",
+		SuggestedFilename: "export.csv",
+	})
+	if tm.HandleDownloadProgress(&browser.EventDownloadProgress{GUID: "guid-1", TotalBytes: 2048, ReceivedBytes: 512, State: browser.DownloadProgressStateInProgress}) {
+		t.Error("expected in-progress download not to be finished")
+	}
+	// Throttled: within downloadProgressInterval of the last report
+	tm.HandleDownloadProgress(&browser.EventDownloadProgress{GUID: "guid-1", TotalBytes: 2048, ReceivedBytes: 1024, State: browser.DownloadProgressStateInProgress})
+	if !tm.HandleDownloadProgress(&browser.EventDownloadProgress{
+		GUID:          "guid-1",
+		TotalBytes:    2048,
+		ReceivedBytes: 2048,
+		State:         browser.DownloadProgressStateCompleted,
+		FilePath:      "/tmp/export.csv",
+	}) {
+		t.Error("expected completed download to be finished")
+	}
+
+	if len(tm.downloads) != 0 {
+		t.Errorf("expected finished download to be forgotten, got %d tracked", len(tm.downloads))
+	}
+
+	got := readTestEvents(t, tm, dir)
+	wantTypes := []string{events.EventPageDownloadStarted, events.EventPageDownloadProgress, events.EventPageDownloadCompleted}
+	if len(got) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %d", len(wantTypes), len(got))
+	}
+	for i, want := range wantTypes {
+		if got[i]["event_type"] != want {
+			t.Errorf("event %d: expected %s, got %v", i, want, got[i]["event_type"])
+		}
+	}
+
+	completed := got[2]["data"].(map[string]interface{})
+	if completed["state"] != "completed" || completed["received_bytes"] != float64(2048) || completed["file_path"] != "/tmp/export.csv" {
+		t.Errorf("unexpected completion data: %v", completed)
+	}
+	if completed["url"] != "https://example.com/export.csv" || completed["suggested_filename"] != "export.csv" {
+		t.Errorf("expected completion to carry the download's URL and filename, got %v", completed)
+	}
+}
+
+func TestDownloadDataURLTruncated(t *testing.T) {
+	url := "data:text/csv;base64," + strings.Repeat("QQ", 1000)
+
+	got := downloadURL(url)
+	if len(got) != maxDownloadURLLength+len("...") || !strings.HasSuffix(got, "...") {
+		t.Errorf("expected data URL to be truncated, got %d bytes", len(got))
+	}
+	if downloadURL("https://example.com/a.csv") != "https://example.com/a.csv" {
+		t.Error("expected regular URLs to be left alone")
+	}
+}
+
+func TestOwnsFrame(t *testing.T) {
+	tm, _ := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&page.EventFrameAttached{FrameID: "child", ParentFrameID: "target-1"})
+
+	if !tm.OwnsFrame("target-1") || !tm.OwnsFrame("child") {
+		t.Error("expected main and child frames to belong to the tab")
+	}
+	if tm.OwnsFrame("other") {
+		t.Error("expected unknown frame not to belong to the tab")
+	}
+
+	tm.handleEvent(&page.EventFrameDetached{FrameID: "child", Reason: page.FrameDetachedReasonRemove})
+	if tm.OwnsFrame("child") {
+		t.Error("expected detached frame to be forgotten")
+	}
+}
@@ -0,0 +1,51 @@
+package monitor
+
+import (
+	"github.com/chromedp/cdproto/network"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// sseContentType is the content type of Server-Sent Events streams.
+const sseContentType = "text/event-stream"
+
+// trackEventSource remembers the URL of an EventSource request so its
+// messages can be correlated with the originating network.request.
+func (tm *TabMonitor) trackEventSource(requestID network.RequestID, url string) {
+	tm.trackerMu.Lock()
+	tm.eventSources[requestID] = url
+	tm.trackerMu.Unlock()
+}
+
+// forgetEventSource stops tracking an EventSource request once its stream ends.
+func (tm *TabMonitor) forgetEventSource(requestID network.RequestID) {
+	tm.trackerMu.Lock()
+	delete(tm.eventSources, requestID)
+	tm.trackerMu.Unlock()
+}
+
+// handleEventSourceMessage logs a single Server-Sent Events message. Message
+// data is treated like a response body: it is only logged with body capture
+// on and text/event-stream allowed by body_content_types, then redacted and
+// truncated to the body size limit.
+func (tm *TabMonitor) handleEventSourceMessage(ev *network.EventEventSourceMessageReceived, site, tabID string) {
+	tm.trackerMu.RLock()
+	url := tm.eventSources[ev.RequestID]
+	tm.trackerMu.RUnlock()
+
+	var data string
+	var truncated bool
+	if tm.config.CaptureBodies && tm.shouldCaptureBody(sseContentType, 0) {
+		data, truncated = truncatePayload(tm.redactor.RedactBody(ev.Data), tm.config.BodySizeLimitKB*1024)
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkSSEMessage, &events.SSEMessageData{
+		RequestID:  ev.RequestID.String(),
+		URL:        url,
+		EventName:  ev.EventName,
+		EventID:    ev.EventID,
+		Data:       data,
+		DataLength: len(ev.Data),
+		Truncated:  truncated,
+	}))
+}
@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestEventSourceMessageCorrelation(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CaptureBodies = true
+	tm, dir := newTestMonitor(t, cfg)
+	id := network.RequestID("sse-1")
+
+	tm.handleEvent(&network.EventRequestWillBeSent{
+		RequestID: id,
+		Type:      network.ResourceTypeEventSource,
+		Request:   &network.Request{URL: "https://example.com/stream", Method: "GET"},
+	})
+	tm.handleEvent(&network.EventEventSourceMessageReceived{
+		RequestID: id,
+		EventName: "token",
+		EventID:   "42",
+		Data:      `{"text":"hi","access_token":"abc"}`,
+	})
+	tm.handleEvent(&network.EventLoadingFinished{RequestID: id})
+
+	if len(tm.eventSources) != 0 {
+		t.Errorf("expected EventSource to be forgotten after loading finished, got %d tracked", len(tm.eventSources))
+	}
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(got))
+	}
+	if got[2]["event_type"] != events.EventNetworkFinished {
+		t.Errorf("expected %s, got %v", events.EventNetworkFinished, got[2]["event_type"])
+	}
+	if got[1]["event_type"] != events.EventNetworkSSEMessage {
+		t.Fatalf("expected %s, got %v", events.EventNetworkSSEMessage, got[1]["event_type"])
+	}
+
+	data := got[1]["data"].(map[string]interface{})
+	if data["request_id"] != "sse-1" || data["url"] != "https://example.com/stream" {
+		t.Errorf("expected message to be correlated with its request, got %v", data)
+	}
+	if data["event_name"] != "token" || data["event_id"] != "42" {
+		t.Errorf("unexpected event name/id: %v", data)
+	}
+	if strings.Contains(data["data"].(string), "abc") {
+		t.Errorf("expected message data to be redacted, got %v", data["data"])
+	}
+}
+
+func TestEventSourceMessageTruncated(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CaptureBodies = true
+	cfg.BodySizeLimitKB = 1
+	tm, dir := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&network.EventEventSourceMessageReceived{
+		RequestID: "sse-1",
+		Data:      strings.Repeat("x", 2048),
+	})
+
+	got := readTestEvents(t, tm, dir)
+	data := got[0]["data"].(map[string]interface{})
+	if data["truncated"] != true {
+		t.Error("expected oversized message to be truncated")
+	}
+	if len(data["data"].(string)) != 1024 {
+		t.Errorf("expected data truncated to 1024 bytes, got %d", len(data["data"].(string)))
+	}
+	if data["data_length"] != float64(2048) {
+		t.Errorf("expected data_length 2048, got %v", data["data_length"])
+	}
+}
+
+func TestEventSourceMessageDataGated(t *testing.T) {
+	tests := []struct {
+		name         string
+		capture      bool
+		contentTypes []string
+	}{
+		{"body capture off", false, []string{"text/*"}},
+		{"content type not allowed", true, []string{"application/json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.CaptureBodies = tt.capture
+			cfg.BodyContentTypes = tt.contentTypes
+			tm, dir := newTestMonitor(t, cfg)
+
+			tm.handleEvent(&network.EventEventSourceMessageReceived{RequestID: "sse-1", Data: "hello"})
+
+			got := readTestEvents(t, tm, dir)
+			data := got[0]["data"].(map[string]interface{})
+			if data["data"] != nil {
+				t.Errorf("expected message data to be omitted, got %v", data["data"])
+			}
+			if data["data_length"] != float64(5) {
+				t.Errorf("expected data_length 5, got %v", data["data_length"])
+			}
+		})
+	}
+}
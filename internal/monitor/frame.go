@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"github.com/chromedp/cdproto/page"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// TargetTypeIframe is the CDP target type for out-of-process iframes.
+const TargetTypeIframe = "iframe"
+
+// handleFrameEvent processes frame lifecycle events from the Page domain.
+func (tm *TabMonitor) handleFrameEvent(ev interface{}, site, tabID string) {
+	switch ev := ev.(type) {
+	case *page.EventFrameAttached:
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPageFrameAttached, &events.FrameAttachedData{
+			FrameID:       ev.FrameID.String(),
+			ParentFrameID: ev.ParentFrameID.String(),
+		}))
+
+	case *page.EventFrameDetached:
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPageFrameDetached, &events.FrameDetachedData{
+			FrameID: ev.FrameID.String(),
+			Reason:  ev.Reason.String(),
+		}))
+
+	case *page.EventFrameNavigated:
+		tm.handleFrameNavigated(ev, site, tabID)
+
+	case *page.EventFrameStartedNavigating:
+		tm.handleFrameStartedNavigating(ev)
+
+	case *page.EventNavigatedWithinDocument:
+		tm.handleNavigatedWithinDocument(ev, site, tabID)
+	}
+}
+
+// handleFrameNavigated logs a navigation. Main frame navigations of a tab
+// become page.navigate (or page.reload); all other frames produce
+// page.frame_navigated.
+func (tm *TabMonitor) handleFrameNavigated(ev *page.EventFrameNavigated, site, tabID string) {
+	frame := ev.Frame
+	if frame == nil {
+		return
+	}
+
+	if tm.origin.FrameID == "" && frame.ParentID == "" {
+		tm.commitNavigation(ev, site, tabID)
+		return
+	}
+
+	// Keep frame_url tags current for an out-of-process iframe's own frame
+	if string(frame.ID) == tm.origin.FrameID {
+		tm.mu.Lock()
+		tm.currentURL = frame.URL
+		tm.mu.Unlock()
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPageFrameNavigated, &events.FrameNavigatedData{
+		FrameID:       frame.ID.String(),
+		ParentFrameID: frame.ParentID.String(),
+		Name:          frame.Name,
+		URL:           frame.URL,
+	}))
+}
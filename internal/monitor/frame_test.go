@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/target"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestFrameLifecycleEvents(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&page.EventFrameAttached{FrameID: "child", ParentFrameID: "main"})
+	tm.handleEvent(&page.EventFrameNavigated{Frame: &cdp.Frame{ID: "child", ParentID: "main", URL: "https://pay.example.net/widget"}})
+	tm.handleEvent(&page.EventFrameNavigated{Frame: &cdp.Frame{ID: "main", URL: "https://example.com/checkout"}})
+	tm.handleEvent(&page.EventFrameDetached{FrameID: "child", Reason: page.FrameDetachedReasonRemove})
+
+	if url := tm.CurrentURL(); url != "https://example.com/checkout" {
+		t.Errorf("expected main frame navigation to update currentURL, got %s", url)
+	}
+
+	got := readTestEvents(t, tm, dir)
+	wantTypes := []string{
+		events.EventPageFrameAttached,
+		events.EventPageFrameNavigated,
+		events.EventPageNavigate,
+		events.EventPageFrameDetached,
+	}
+	if len(got) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %d", len(wantTypes), len(got))
+	}
+	for i, want := range wantTypes {
+		if got[i]["event_type"] != want {
+			t.Errorf("event %d: expected %s, got %v", i, want, got[i]["event_type"])
+		}
+	}
+
+	navigated := got[1]["data"].(map[string]interface{})
+	if navigated["frame_id"] != "child" || navigated["url"] != "https://pay.example.net/widget" {
+		t.Errorf("unexpected frame_navigated data: %v", navigated)
+	}
+	detached := got[3]["data"].(map[string]interface{})
+	if detached["reason"] != "remove" {
+		t.Errorf("expected detach reason remove, got %v", detached["reason"])
+	}
+}
+
+func TestOutOfProcessFrameTagging(t *testing.T) {
+	cfg := config.DefaultConfig()
+	tm, dir := newTestMonitor(t, cfg)
+
+	child := NewTabMonitor(tm.ctx, "frame-1", tm.tabID, "", "", "about:blank", "", tm.sink, cfg)
+	child.parent = tm
+	child.origin = eventOrigin{FrameID: "frame-1"}
+
+	// The iframe's own navigation updates frame_url but is not a tab navigation
+	child.handleEvent(&page.EventFrameNavigated{Frame: &cdp.Frame{ID: "frame-1", ParentID: "main", URL: "https://auth.example.net/login"}})
+	child.handleEvent(&page.EventLoadEventFired{})
+	child.handleEvent(&network.EventLoadingFailed{RequestID: "req-1", ErrorText: "net::ERR_BLOCKED_BY_CLIENT"})
+
+	if url := tm.CurrentURL(); url != "https://example.com" {
+		t.Errorf("expected iframe navigation to leave tab URL unchanged, got %s", url)
+	}
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events (page.load suppressed for frames), got %d", len(got))
+	}
+	if got[0]["event_type"] != events.EventPageFrameNavigated {
+		t.Errorf("expected %s, got %v", events.EventPageFrameNavigated, got[0]["event_type"])
+	}
+
+	failure := got[1]
+	if failure["frame_id"] != "frame-1" || failure["frame_url"] != "https://auth.example.net/login" {
+		t.Errorf("expected frame tags, got frame_id=%v frame_url=%v", failure["frame_id"], failure["frame_url"])
+	}
+	if failure["tab_id"] != "tab-1" {
+		t.Errorf("expected frame event under parent tab, got %v", failure["tab_id"])
+	}
+}
+
+func TestHandleAttachedToTargetRespectsConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableFrames = false
+	cfg.EnableWorkers = false
+	tm, _ := newTestMonitor(t, cfg)
+	tm.browserCtx = tm.ctx
+
+	tm.handleAttachedToTarget("session-1", &target.Info{TargetID: "frame-1", Type: TargetTypeIframe})
+	tm.handleAttachedToTarget("session-2", &target.Info{TargetID: "worker-1", Type: TargetTypeWorker})
+	tm.handleAttachedToTarget("session-3", nil)
+
+	if len(tm.children) != 0 {
+		t.Errorf("expected no children when frames and workers are disabled, got %d", len(tm.children))
+	}
+}
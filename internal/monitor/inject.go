@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// injectedWorld is the isolated world injected scripts run in. Page scripts
+// can't see the bindings or tamper with the observers, and the scripts can't
+// disturb the page; the world still shares the DOM and performance timeline.
+const injectedWorld = "browser_tail"
+
+// injectScript exposes binding to the injected world and installs script in
+// that world in every new document, and in the current one in case it has
+// already loaded. Scripts report back by calling the binding with a JSON
+// payload.
+func injectScript(targetCtx context.Context, binding, script string) error {
+	return chromedp.Run(targetCtx,
+		runtime.AddBinding(binding).WithExecutionContextName(injectedWorld),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			_, err := page.AddScriptToEvaluateOnNewDocument(script).WithWorldName(injectedWorld).Do(ctx)
+			return err
+		}),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			tree, err := page.GetFrameTree().Do(ctx)
+			if err != nil {
+				return err
+			}
+			worldID, err := page.CreateIsolatedWorld(tree.Frame.ID).WithWorldName(injectedWorld).Do(ctx)
+			if err != nil {
+				return err
+			}
+			// Exceptions in the page are not fatal; the next document retries
+			_, _, err = runtime.Evaluate(script).WithContextID(worldID).Do(ctx)
+			return err
+		}),
+	)
+}
+
+// handleBindingCalled dispatches reports from injected scripts.
+// Payloads come from the page, so handlers only keep known fields.
+func (tm *TabMonitor) handleBindingCalled(ev *runtime.EventBindingCalled, site, tabID string) {
+	switch ev.Name {
+	case webVitalsBinding:
+		if tm.config.EnableWebVitals {
+			tm.handleWebVitals(ev.Payload, site, tabID)
+		}
+	case longTaskBinding:
+		if tm.config.EnableLongTasks {
+			tm.handleLongTasks(ev.Payload, site, tabID)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Reports main-thread tasks longer than 50ms to browser_tail through the
+// __browserTailLongTask binding, batched per observer callback. Runs in the
+// top-level document only; iframe tasks show up there with attribution.
+(function () {
+  'use strict';
+
+  var send = window.__browserTailLongTask;
+  if (typeof send !== 'function' || window.top !== window || window.__browserTailLongTaskInstalled) {
+    return;
+  }
+  Object.defineProperty(window, '__browserTailLongTaskInstalled', { value: true });
+
+  try {
+    new PerformanceObserver(function (list) {
+      var tasks = list.getEntries().map(function (entry) {
+        var attribution = (entry.attribution && entry.attribution[0]) || {};
+        return {
+          url: location.href,
+          name: entry.name,
+          start_time_ms: entry.startTime,
+          duration_ms: entry.duration,
+          container_type: attribution.containerType || '',
+          container_src: attribution.containerSrc || '',
+          container_id: attribution.containerId || '',
+          container_name: attribution.containerName || ''
+        };
+      });
+      try {
+        send(JSON.stringify(tasks));
+      } catch (e) {
+        // Binding removed (monitor detached)
+      }
+    }).observe({ type: 'longtask', buffered: true });
+  } catch (e) {
+    // Long tasks not supported by this browser
+  }
+})();
@@ -0,0 +1,147 @@
+package monitor
+
+import (
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// Navigation types reported in page.navigate and page.reload events.
+const (
+	NavigationTypeNavigation       = "navigation"
+	NavigationTypeReload           = "reload"
+	NavigationTypeReloadNoCache    = "reload_bypassing_cache"
+	NavigationTypeBackForward      = "back_forward"
+	NavigationTypeBackForwardCache = "back_forward_cache"
+	NavigationTypeRestore          = "restore"
+	NavigationTypeSameDocument     = "same_document"
+)
+
+// pendingNavigation holds what is known about a main frame navigation that
+// has started but not yet committed.
+type pendingNavigation struct {
+	Type     string
+	Referrer string
+}
+
+// isMainFrame reports whether frameID is this tab's main frame.
+// Out-of-process iframe monitors never have a main frame.
+func (tm *TabMonitor) isMainFrame(frameID string) bool {
+	if tm.origin.FrameID != "" {
+		return false
+	}
+
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	// The main frame of a page target shares the target's ID
+	if tm.mainFrameID == "" {
+		return frameID == tm.targetID
+	}
+	return frameID == tm.mainFrameID
+}
+
+// handleFrameStartedNavigating records the type of a cross-document main
+// frame navigation so it can be reported once the navigation commits.
+func (tm *TabMonitor) handleFrameStartedNavigating(ev *page.EventFrameStartedNavigating) {
+	if !tm.isMainFrame(ev.FrameID.String()) {
+		return
+	}
+
+	var navType string
+	switch ev.NavigationType {
+	case page.FrameStartedNavigatingNavigationTypeReload:
+		navType = NavigationTypeReload
+	case page.FrameStartedNavigatingNavigationTypeReloadBypassingCache:
+		navType = NavigationTypeReloadNoCache
+	case page.FrameStartedNavigatingNavigationTypeHistoryDifferentDocument:
+		navType = NavigationTypeBackForward
+	case page.FrameStartedNavigatingNavigationTypeRestore,
+		page.FrameStartedNavigatingNavigationTypeRestoreWithPost:
+		navType = NavigationTypeRestore
+	case page.FrameStartedNavigatingNavigationTypeDifferentDocument:
+		navType = NavigationTypeNavigation
+	default:
+		// Same-document navigations are reported by navigatedWithinDocument
+		return
+	}
+
+	tm.mu.Lock()
+	tm.pendingNav.Type = navType
+	tm.mu.Unlock()
+}
+
+// trackNavigationReferrer records the Referer of a main frame document
+// request, which becomes the new document's referrer.
+func (tm *TabMonitor) trackNavigationReferrer(ev *network.EventRequestWillBeSent) {
+	// Navigation requests share their ID with the loader they create
+	if ev.Type != network.ResourceTypeDocument || ev.LoaderID.String() != ev.RequestID.String() {
+		return
+	}
+	if !tm.isMainFrame(ev.FrameID.String()) {
+		return
+	}
+
+	referrer := headerValue(ev.Request.Headers, "Referer")
+
+	tm.mu.Lock()
+	tm.pendingNav.Referrer = referrer
+	tm.mu.Unlock()
+}
+
+// commitNavigation logs a committed main frame navigation as page.navigate,
+// or page.reload if it reloaded the current document.
+func (tm *TabMonitor) commitNavigation(ev *page.EventFrameNavigated, site, tabID string) {
+	frame := ev.Frame
+
+	tm.mu.Lock()
+	tm.currentURL = frame.URL
+	tm.mainFrameID = frame.ID.String()
+	pending := tm.pendingNav
+	tm.pendingNav = pendingNavigation{}
+	tm.mu.Unlock()
+
+	navType := pending.Type
+	if ev.Type == page.NavigationTypeBackForwardCacheRestore {
+		navType = NavigationTypeBackForwardCache
+	}
+	if navType == "" {
+		navType = NavigationTypeNavigation
+	}
+
+	event := events.NewPageNavigateEvent(site, tabID, frame.URL, pending.Referrer, navType)
+	if navType == NavigationTypeReload || navType == NavigationTypeReloadNoCache {
+		event.EventType = events.EventPageReload
+	}
+	tm.writeEvent(event)
+}
+
+// handleNavigatedWithinDocument logs history API and fragment navigations,
+// which change the URL without loading a new document.
+func (tm *TabMonitor) handleNavigatedWithinDocument(ev *page.EventNavigatedWithinDocument, site, tabID string) {
+	frameID := ev.FrameID.String()
+
+	if tm.isMainFrame(frameID) {
+		tm.mu.Lock()
+		referrer := tm.currentURL
+		tm.currentURL = ev.URL
+		tm.mu.Unlock()
+
+		tm.writeEvent(events.NewPageNavigateEvent(site, tabID, ev.URL, referrer, NavigationTypeSameDocument))
+		return
+	}
+
+	// Keep frame_url tags current for an out-of-process iframe's own frame
+	if frameID == tm.origin.FrameID {
+		tm.mu.Lock()
+		tm.currentURL = ev.URL
+		tm.mu.Unlock()
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPageFrameNavigated, &events.FrameNavigatedData{
+		FrameID:        frameID,
+		URL:            ev.URL,
+		NavigationType: NavigationTypeSameDocument,
+	}))
+}
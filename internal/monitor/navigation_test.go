@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestNavigationReferrerAndReload(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	// Cross-document navigation from a link
+	tm.handleEvent(&page.EventFrameStartedNavigating{FrameID: "target-1", URL: "https://example.com/a", NavigationType: page.FrameStartedNavigatingNavigationTypeDifferentDocument})
+	tm.handleEvent(&network.EventRequestWillBeSent{
+		RequestID: "loader-1",
+		LoaderID:  "loader-1",
+		FrameID:   "target-1",
+		Type:      network.ResourceTypeDocument,
+		Request:   &network.Request{URL: "https://example.com/a", Method: "GET", Headers: network.Headers{"Referer": "https://example.com/"}},
+	})
+	tm.handleEvent(&page.EventFrameNavigated{Frame: &cdp.Frame{ID: "target-1", URL: "https://example.com/a"}, Type: page.NavigationTypeNavigation})
+
+	// Reload of the same document
+	tm.handleEvent(&page.EventFrameStartedNavigating{FrameID: "target-1", URL: "https://example.com/a", NavigationType: page.FrameStartedNavigatingNavigationTypeReload})
+	tm.handleEvent(&page.EventFrameNavigated{Frame: &cdp.Frame{ID: "target-1", URL: "https://example.com/a"}, Type: page.NavigationTypeNavigation})
+
+	// Restore from the back/forward cache
+	tm.handleEvent(&page.EventFrameNavigated{Frame: &cdp.Frame{ID: "target-1", URL: "https://example.com/b"}, Type: page.NavigationTypeBackForwardCacheRestore})
+
+	got := readTestEvents(t, tm, dir)
+	want := []struct {
+		eventType, navType, referrer string
+	}{
+		{events.EventPageNavigate, NavigationTypeNavigation, "https://example.com/"},
+		{events.EventPageReload, NavigationTypeReload, ""},
+		{events.EventPageNavigate, NavigationTypeBackForwardCache, ""},
+	}
+
+	// The document request is logged too
+	var navigations []map[string]interface{}
+	for _, ev := range got {
+		if ev["event_type"] != events.EventNetworkRequest {
+			navigations = append(navigations, ev)
+		}
+	}
+	if len(navigations) != len(want) {
+		t.Fatalf("expected %d navigation events, got %d", len(want), len(navigations))
+	}
+	for i, w := range want {
+		data := navigations[i]["data"].(map[string]interface{})
+		if navigations[i]["event_type"] != w.eventType || data["navigation_type"] != w.navType || data["referrer"] != w.referrer {
+			t.Errorf("event %d: got %v %v, want %s %s referrer=%q", i, navigations[i]["event_type"], data, w.eventType, w.navType, w.referrer)
+		}
+	}
+}
+
+func TestSameDocumentNavigation(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&page.EventNavigatedWithinDocument{FrameID: "target-1", URL: "https://example.com/settings", NavigationType: page.NavigatedWithinDocumentNavigationTypeHistoryAPI})
+	tm.handleEvent(&page.EventNavigatedWithinDocument{FrameID: "child", URL: "https://widget.example.net/#tab2", NavigationType: page.NavigatedWithinDocumentNavigationTypeFragment})
+
+	if url := tm.CurrentURL(); url != "https://example.com/settings" {
+		t.Errorf("expected route change to update currentURL, got %s", url)
+	}
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+
+	if got[0]["event_type"] != events.EventPageNavigate {
+		t.Fatalf("expected %s, got %v", events.EventPageNavigate, got[0]["event_type"])
+	}
+	nav := got[0]["data"].(map[string]interface{})
+	if nav["navigation_type"] != NavigationTypeSameDocument || nav["referrer"] != "https://example.com" {
+		t.Errorf("unexpected same-document navigation data: %v", nav)
+	}
+
+	if got[1]["event_type"] != events.EventPageFrameNavigated {
+		t.Fatalf("expected %s, got %v", events.EventPageFrameNavigated, got[1]["event_type"])
+	}
+	if frame := got[1]["data"].(map[string]interface{}); frame["navigation_type"] != NavigationTypeSameDocument {
+		t.Errorf("expected child frame navigation to be same_document, got %v", frame)
+	}
+}
@@ -0,0 +1,161 @@
+package monitor
+
+import (
+	"log"
+	"sync"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// outputQueue runs a tab's sink writes in order on one goroutine. Events
+// that need source maps are symbolicated there, since fetching a map over
+// CDP cannot happen in an event listener, without reordering the log.
+type outputQueue struct {
+	mu     sync.Mutex
+	ops    []func()
+	closed bool
+	wake   chan struct{}
+	done   chan struct{}
+}
+
+func newOutputQueue() *outputQueue {
+	q := &outputQueue{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// push queues op. It never blocks, so it is safe to call from event
+// listeners. Returns false once the queue is closed.
+func (q *outputQueue) push(op func()) bool {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return false
+	}
+	q.ops = append(q.ops, op)
+	q.mu.Unlock()
+
+	q.signal()
+	return true
+}
+
+// close stops the queue once the ops already queued have run.
+func (q *outputQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	q.signal()
+}
+
+func (q *outputQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *outputQueue) run() {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		ops := q.ops
+		q.ops = nil
+		closed := q.closed
+		q.mu.Unlock()
+
+		for _, op := range ops {
+			op()
+		}
+
+		if len(ops) == 0 {
+			if closed {
+				return
+			}
+			<-q.wake
+		}
+	}
+}
+
+// topLevel tags ev with the workers and frames it came from, innermost
+// origin first, and returns the monitor whose log it belongs in.
+func (tm *TabMonitor) topLevel(ev *events.LogEvent) *TabMonitor {
+	m := tm
+	for {
+		if m.origin.WorkerID != "" && ev.WorkerID == "" {
+			ev.WorkerID = m.origin.WorkerID
+			ev.WorkerType = m.origin.WorkerType
+		}
+		if m.origin.FrameID != "" && ev.FrameID == "" {
+			ev.FrameID = m.origin.FrameID
+			ev.FrameURL = m.CurrentURL()
+		}
+		if m.parent == nil {
+			return m
+		}
+		m = m.parent
+	}
+}
+
+// output runs op, a write to the sink, after any writes already queued.
+// Without a queue it runs straight away. Once a queued monitor has stopped
+// op is dropped, so a closed log is not reopened.
+func (tm *TabMonitor) output(op func()) {
+	tm.outMu.Lock()
+	q := tm.out
+	tm.outMu.Unlock()
+
+	if q == nil {
+		op()
+		return
+	}
+	q.push(op)
+}
+
+// enqueue runs op on the output queue, starting the queue if needed.
+func (tm *TabMonitor) enqueue(op func()) {
+	tm.outMu.Lock()
+	if tm.out == nil && !tm.outClosed {
+		tm.out = newOutputQueue()
+	}
+	q := tm.out
+	tm.outMu.Unlock()
+
+	if q != nil {
+		q.push(op)
+	}
+}
+
+// closeOutput lets queued writes finish and stops queueing new ones.
+func (tm *TabMonitor) closeOutput() {
+	tm.outMu.Lock()
+	tm.outClosed = true
+	q := tm.out
+	tm.outMu.Unlock()
+
+	if q != nil {
+		q.close()
+	}
+}
+
+// Wait blocks until events queued before Stop have been written.
+func (tm *TabMonitor) Wait() {
+	tm.outMu.Lock()
+	q := tm.out
+	tm.outMu.Unlock()
+
+	if q != nil {
+		<-q.done
+	}
+}
+
+// writeToSink writes an event to this monitor's log.
+func (tm *TabMonitor) writeToSink(ev *events.LogEvent) {
+	if err := tm.sink.WriteEvent(tm.tabID, ev); err != nil {
+		log.Printf("Warning: failed to write event (tab %s, type %s): %v",
+			tm.tabID, ev.EventType, err)
+	}
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/chromedp/cdproto/performance"
+	"github.com/chromedp/chromedp"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+const (
+	// longTaskBinding is the Runtime binding the long task observer reports through.
+	longTaskBinding = "__browserTailLongTask"
+
+	// maxLongTasksPerReport bounds how many tasks one binding call may log.
+	maxLongTasksPerReport = 100
+)
+
+// longTaskScript observes main-thread tasks longer than 50ms from the page's
+// top-level document and reports them through longTaskBinding. Tasks in
+// same-process iframes are attributed to their container; out-of-process
+// iframes have their own main thread and are not observed.
+//
+//go:embed long_tasks.js
+var longTaskScript string
+
+// enableLongTasks installs the long task observer.
+func enableLongTasks(targetCtx context.Context) error {
+	return injectScript(targetCtx, longTaskBinding, longTaskScript)
+}
+
+// handleLongTasks logs a batch of long tasks reported by the injected script.
+func (tm *TabMonitor) handleLongTasks(payload, site, tabID string) {
+	var tasks []events.LongTaskData
+	if err := json.Unmarshal([]byte(payload), &tasks); err != nil {
+		return
+	}
+	if len(tasks) > maxLongTasksPerReport {
+		tasks = tasks[:maxLongTasksPerReport]
+	}
+
+	for i := range tasks {
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPerfLongTask, &tasks[i]))
+	}
+}
+
+// runPerfMetrics samples Performance.getMetrics at the configured interval
+// until the monitor or its target goes away.
+func (tm *TabMonitor) runPerfMetrics(targetCtx context.Context, interval time.Duration) {
+	if err := chromedp.Run(targetCtx, performance.Enable()); err != nil {
+		log.Printf("Warning: failed to enable performance metrics (tab %s): %v", tm.tabID, err)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tm.ctx.Done():
+			return
+		case <-targetCtx.Done():
+			return
+		case <-ticker.C:
+			tm.samplePerfMetrics(targetCtx)
+		}
+	}
+}
+
+// samplePerfMetrics writes a single perf.metrics event.
+func (tm *TabMonitor) samplePerfMetrics(targetCtx context.Context) {
+	var metrics []*performance.Metric
+	err := chromedp.Run(targetCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		metrics, err = performance.GetMetrics().Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return
+	}
+
+	site, tabID := tm.siteAndTab()
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPerfMetrics, convertMetrics(metrics)))
+}
+
+// convertMetrics maps Performance.getMetrics results onto PerfMetricsData.
+// CDP reports durations in seconds; they are converted to milliseconds.
+func convertMetrics(metrics []*performance.Metric) *events.PerfMetricsData {
+	data := &events.PerfMetricsData{}
+	for _, m := range metrics {
+		if m == nil {
+			continue
+		}
+		switch m.Name {
+		case "JSHeapUsedSize":
+			data.JSHeapUsedBytes = int64(m.Value)
+		case "JSHeapTotalSize":
+			data.JSHeapTotalBytes = int64(m.Value)
+		case "Nodes":
+			data.Nodes = int64(m.Value)
+		case "Documents":
+			data.Documents = int64(m.Value)
+		case "Frames":
+			data.Frames = int64(m.Value)
+		case "JSEventListeners":
+			data.JSEventListeners = int64(m.Value)
+		case "LayoutCount":
+			data.LayoutCount = int64(m.Value)
+		case "RecalcStyleCount":
+			data.RecalcStyleCount = int64(m.Value)
+		case "LayoutDuration":
+			data.LayoutDurationMs = roundMs(m.Value * 1000)
+		case "RecalcStyleDuration":
+			data.RecalcStyleDurationMs = roundMs(m.Value * 1000)
+		case "ScriptDuration":
+			data.ScriptDurationMs = roundMs(m.Value * 1000)
+		case "TaskDuration":
+			data.TaskDurationMs = roundMs(m.Value * 1000)
+		}
+	}
+	return data
+}
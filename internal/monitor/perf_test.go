@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/performance"
+	"github.com/chromedp/cdproto/runtime"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestLongTaskBinding(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableLongTasks = true
+	tm, dir := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&runtime.EventBindingCalled{
+		Name: longTaskBinding,
+		Payload: `[{"url":"https://example.com/","name":"self","start_time_ms":1500.2,"duration_ms":180},` +
+			`{"url":"https://example.com/","name":"cross-origin-descendant","start_time_ms":2100,"duration_ms":75,"container_type":"iframe","container_src":"https://ads.example.net/"}]`,
+	})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	for i, ev := range got {
+		if ev["event_type"] != events.EventPerfLongTask {
+			t.Errorf("event %d: expected %s, got %v", i, events.EventPerfLongTask, ev["event_type"])
+		}
+	}
+
+	first := got[0]["data"].(map[string]interface{})
+	if first["duration_ms"] != float64(180) || first["start_time_ms"] != 1500.2 {
+		t.Errorf("unexpected long task timing: %v", first)
+	}
+	second := got[1]["data"].(map[string]interface{})
+	if second["container_src"] != "https://ads.example.net/" {
+		t.Errorf("expected iframe attribution, got %v", second)
+	}
+}
+
+func TestLongTasksDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableLongTasks = false
+	tm, _ := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&runtime.EventBindingCalled{Name: longTaskBinding, Payload: `[{"duration_ms":100}]`})
+
+	if openTestFiles(tm) != 0 {
+		t.Error("expected no events to be written when long tasks are disabled")
+	}
+}
+
+func TestConvertMetrics(t *testing.T) {
+	data := convertMetrics([]*performance.Metric{
+		{Name: "JSHeapUsedSize", Value: 1048576},
+		{Name: "Nodes", Value: 1200},
+		{Name: "LayoutCount", Value: 14},
+		{Name: "ScriptDuration", Value: 0.25},
+		{Name: "Timestamp", Value: 12345.6},
+		nil,
+	})
+
+	if data.JSHeapUsedBytes != 1048576 || data.Nodes != 1200 || data.LayoutCount != 14 {
+		t.Errorf("unexpected counts: %+v", data)
+	}
+	if data.ScriptDurationMs != 250 {
+		t.Errorf("expected script duration 250ms, got %v", data.ScriptDurationMs)
+	}
+}
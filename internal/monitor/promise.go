@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"strings"
+
+	"github.com/chromedp/cdproto/runtime"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// unhandledRejectionPrefix starts the exception text V8 reports for promise
+// rejections that have no handler by the end of the microtask checkpoint.
+const unhandledRejectionPrefix = "Uncaught (in promise)"
+
+// isUnhandledRejection reports whether an exception is an unhandled promise
+// rejection rather than a synchronously thrown error.
+func isUnhandledRejection(details *runtime.ExceptionDetails) bool {
+	return details != nil && strings.HasPrefix(details.Text, unhandledRejectionPrefix)
+}
+
+// handleUnhandledRejection logs an unhandled promise rejection with its
+// reason value and stack.
+func (tm *TabMonitor) handleUnhandledRejection(details *runtime.ExceptionDetails, site, tabID string) {
+	class, message := exceptionClassAndMessage(details.Exception)
+	tm.writeSymbolicated(events.NewLogEvent(site, tabID, events.EventErrorUnhandledPromise, &events.UnhandledPromiseData{
+		ExceptionID:   details.ExceptionID,
+		Text:          details.Text,
+		Reason:        extractRemoteObjectValue(details.Exception),
+		ReasonClass:   class,
+		ReasonMessage: message,
+		Line:          details.LineNumber,
+		Column:        details.ColumnNumber,
+		URL:           details.URL,
+		ScriptID:      string(details.ScriptID),
+		Stack:         convertStackTrace(details.StackTrace),
+	}))
+}
+
+// handleExceptionRevoked logs a rejection that gained a handler after it was
+// reported as unhandled.
+func (tm *TabMonitor) handleExceptionRevoked(ev *runtime.EventExceptionRevoked, site, tabID string) {
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventErrorPromiseHandled, &events.PromiseHandledData{
+		ExceptionID: ev.ExceptionID,
+		Reason:      ev.Reason,
+	}))
+}
@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/runtime"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestUnhandledRejection(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&runtime.EventExceptionThrown{ExceptionDetails: &runtime.ExceptionDetails{
+		ExceptionID: 7,
+		Text:        "Uncaught (in promise)",
+		Exception: &runtime.RemoteObject{
+			Type:        runtime.TypeObject,
+			Subtype:     runtime.SubtypeError,
+			ClassName:   "Error",
+			Description: "Error: fetch failed\n    at load (app.js:3:9)",
+		},
+		StackTrace: &runtime.StackTrace{CallFrames: []*runtime.CallFrame{
+			{FunctionName: "load", URL: "https://example.com/app.js", LineNumber: 2, ColumnNumber: 8},
+		}},
+	}})
+	tm.handleEvent(&runtime.EventExceptionRevoked{ExceptionID: 7, Reason: "Handler added to rejected promise"})
+	tm.handleEvent(&runtime.EventExceptionThrown{ExceptionDetails: &runtime.ExceptionDetails{
+		Text:      "Uncaught",
+		Exception: &runtime.RemoteObject{Type: runtime.TypeString, Value: []byte(`"boom"`)},
+	}})
+
+	got := readTestEvents(t, tm, dir)
+	wantTypes := []string{
+		events.EventErrorUnhandledPromise,
+		events.EventErrorPromiseHandled,
+		events.EventErrorRuntime,
+	}
+	if len(got) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %d", len(wantTypes), len(got))
+	}
+	for i, want := range wantTypes {
+		if got[i]["event_type"] != want {
+			t.Errorf("event %d: expected %s, got %v", i, want, got[i]["event_type"])
+		}
+	}
+
+	rejection := got[0]["data"].(map[string]interface{})
+	if rejection["exception_id"] != float64(7) {
+		t.Errorf("expected exception_id 7, got %v", rejection["exception_id"])
+	}
+	if rejection["reason_class"] != "Error" || rejection["reason_message"] != "fetch failed" {
+		t.Errorf("unexpected reason fields: %v", rejection)
+	}
+	if stack, ok := rejection["stack"].([]interface{}); !ok || len(stack) != 1 {
+		t.Errorf("expected 1 stack frame, got %v", rejection["stack"])
+	}
+
+	handled := got[1]["data"].(map[string]interface{})
+	if handled["exception_id"] != float64(7) {
+		t.Errorf("expected handled exception_id 7, got %v", handled["exception_id"])
+	}
+}
+
+func TestIsUnhandledRejection(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Uncaught (in promise)", true},
+		{"Uncaught (in promise) TypeError: x", true},
+		{"Uncaught", false},
+		{"Uncaught TypeError: x", false},
+	}
+
+	for _, tt := range tests {
+		if got := isUnhandledRejection(&runtime.ExceptionDetails{Text: tt.text}); got != tt.want {
+			t.Errorf("isUnhandledRejection(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+	if isUnhandledRejection(nil) {
+		t.Error("expected nil details not to be an unhandled rejection")
+	}
+}
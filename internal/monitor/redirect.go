@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"github.com/chromedp/cdproto/network"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// handleRedirect logs the 3xx response that caused a request to be re-sent
+// to a new URL, and records it in the request's redirect chain.
+func (tm *TabMonitor) handleRedirect(ev *network.EventRequestWillBeSent, site, tabID string) {
+	resp := ev.RedirectResponse
+
+	tm.trackerMu.Lock()
+	hop := 1
+	if rt, exists := tm.requestTimings[ev.RequestID]; exists {
+		rt.Redirects = append(rt.Redirects, events.RedirectHop{URL: resp.URL, Status: resp.Status})
+		hop = len(rt.Redirects)
+	}
+	tm.trackerMu.Unlock()
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkRedirect, &events.NetworkRedirectData{
+		RequestID: ev.RequestID.String(),
+		FromURL:   resp.URL,
+		ToURL:     ev.Request.URL,
+		Status:    resp.Status,
+		Location:  headerValue(resp.Headers, "Location"),
+		Hop:       hop,
+	}))
+}
+
+// redirectChain returns the redirects a request has followed so far.
+func (tm *TabMonitor) redirectChain(requestID network.RequestID) []events.RedirectHop {
+	tm.trackerMu.RLock()
+	defer tm.trackerMu.RUnlock()
+
+	rt, exists := tm.requestTimings[requestID]
+	if !exists || len(rt.Redirects) == 0 {
+		return nil
+	}
+	return append([]events.RedirectHop(nil), rt.Redirects...)
+}
@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestRedirectChain(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+	id := network.RequestID("req-1")
+
+	tm.handleEvent(&network.EventRequestWillBeSent{
+		RequestID: id,
+		Request:   &network.Request{URL: "https://example.com/login", Method: "GET"},
+	})
+	tm.handleEvent(&network.EventRequestWillBeSent{
+		RequestID: id,
+		Request:   &network.Request{URL: "https://auth.example.com/authorize", Method: "GET"},
+		RedirectResponse: &network.Response{
+			URL:     "https://example.com/login",
+			Status:  302,
+			Headers: network.Headers{"location": "https://auth.example.com/authorize"},
+		},
+	})
+	tm.handleEvent(&network.EventRequestWillBeSent{
+		RequestID: id,
+		Request:   &network.Request{URL: "https://example.com/callback", Method: "GET"},
+		RedirectResponse: &network.Response{
+			URL:     "https://auth.example.com/authorize",
+			Status:  303,
+			Headers: network.Headers{"Location": "/callback"},
+		},
+	})
+	tm.handleEvent(&network.EventResponseReceived{
+		RequestID: id,
+		Response:  &network.Response{URL: "https://example.com/callback", Status: 200},
+	})
+
+	got := readTestEvents(t, tm, dir)
+	wantTypes := []string{
+		events.EventNetworkRequest,
+		events.EventNetworkRedirect,
+		events.EventNetworkRequest,
+		events.EventNetworkRedirect,
+		events.EventNetworkRequest,
+		events.EventNetworkResponse,
+	}
+	if len(got) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %d", len(wantTypes), len(got))
+	}
+	for i, want := range wantTypes {
+		if got[i]["event_type"] != want {
+			t.Errorf("event %d: expected %s, got %v", i, want, got[i]["event_type"])
+		}
+	}
+
+	second := got[3]["data"].(map[string]interface{})
+	if second["hop"] != float64(2) || second["status"] != float64(303) {
+		t.Errorf("unexpected second redirect: %v", second)
+	}
+	if second["from_url"] != "https://auth.example.com/authorize" || second["to_url"] != "https://example.com/callback" {
+		t.Errorf("unexpected redirect URLs: %v", second)
+	}
+	if second["location"] != "/callback" {
+		t.Errorf("expected Location header, got %v", second["location"])
+	}
+
+	resp := got[5]["data"].(map[string]interface{})
+	chain, ok := resp["redirect_chain"].([]interface{})
+	if !ok || len(chain) != 2 {
+		t.Fatalf("expected 2 hops in redirect chain, got %v", resp["redirect_chain"])
+	}
+	if first := chain[0].(map[string]interface{}); first["url"] != "https://example.com/login" || first["status"] != float64(302) {
+		t.Errorf("unexpected first hop: %v", first)
+	}
+}
+
+func TestResponseWithoutRedirects(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&network.EventRequestWillBeSent{
+		RequestID: "req-1",
+		Request:   &network.Request{URL: "https://example.com/", Method: "GET"},
+	})
+	tm.handleEvent(&network.EventResponseReceived{
+		RequestID: "req-1",
+		Response:  &network.Response{URL: "https://example.com/", Status: 200},
+	})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	if _, ok := got[1]["data"].(map[string]interface{})["redirect_chain"]; ok {
+		t.Error("expected redirect_chain to be omitted when there were no redirects")
+	}
+}
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/security"
+	"github.com/chromedp/chromedp"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// enableSecurity enables the Security domain so Chrome reports changes to
+// the page's visible security state.
+func enableSecurity(targetCtx context.Context) error {
+	return chromedp.Run(targetCtx, security.Enable())
+}
+
+// handleSecurityDetails logs the TLS certificate of a response the first
+// time this tab connects to its origin.
+func (tm *TabMonitor) handleSecurityDetails(requestID network.RequestID, resp *network.Response, site, tabID string) {
+	details := resp.SecurityDetails
+	if details == nil {
+		return
+	}
+	origin := httpOrigin(resp.URL)
+	if origin == "" {
+		return
+	}
+
+	tm.mu.Lock()
+	_, seen := tm.certOrigins[origin]
+	tm.certOrigins[origin] = struct{}{}
+	tm.mu.Unlock()
+
+	if seen {
+		return
+	}
+
+	data := &events.CertificateData{
+		Origin:               origin,
+		RequestID:            requestID.String(),
+		Protocol:             details.Protocol,
+		KeyExchange:          details.KeyExchange,
+		Cipher:               details.Cipher,
+		Subject:              details.SubjectName,
+		Issuer:               details.Issuer,
+		SANs:                 details.SanList,
+		ValidFrom:            formatEpoch(details.ValidFrom),
+		ValidTo:              formatEpoch(details.ValidTo),
+		CTCompliance:         details.CertificateTransparencyCompliance.String(),
+		EncryptedClientHello: details.EncryptedClientHello,
+	}
+	if details.ValidTo != nil {
+		remaining := time.Until(details.ValidTo.Time())
+		data.ExpiresInDays = int(remaining.Hours() / 24)
+		data.Expired = remaining < 0
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventSecurityCertificate, data))
+}
+
+// handleSecurityStateChanged logs changes to the page's security state or
+// its security issues, such as a certificate error or mixed content.
+func (tm *TabMonitor) handleSecurityStateChanged(state *security.VisibleSecurityState, site, tabID string) {
+	if state == nil {
+		return
+	}
+
+	tm.mu.Lock()
+	previous := tm.securityState
+	tm.securityState = state
+	tm.mu.Unlock()
+
+	if previous != nil && previous.SecurityState == state.SecurityState &&
+		slices.Equal(previous.SecurityStateIssueIDs, state.SecurityStateIssueIDs) {
+		return
+	}
+
+	data := &events.SecurityStateChangedData{
+		SecurityState: state.SecurityState.String(),
+		Issues:        state.SecurityStateIssueIDs,
+	}
+	if previous != nil {
+		data.PreviousState = previous.SecurityState.String()
+	}
+	if cert := state.CertificateSecurityState; cert != nil {
+		data.CertificateError = cert.CertificateNetworkError
+		data.CertificateSubject = cert.SubjectName
+		data.CertificateValidTo = formatEpoch(cert.ValidTo)
+		data.ObsoleteTLS = cert.ObsoleteSslProtocol || cert.ObsoleteSslKeyExchange ||
+			cert.ObsoleteSslCipher || cert.ObsoleteSslSignature
+		data.WeakSignature = cert.CertificateHasWeakSignature || cert.CertificateHasSha1signature
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventSecurityStateChanged, data))
+}
+
+// formatEpoch formats a CDP timestamp as RFC 3339, or "" if unset.
+func formatEpoch(t *cdp.TimeSinceEpoch) string {
+	if t == nil {
+		return ""
+	}
+	return t.Time().UTC().Format(time.RFC3339)
+}
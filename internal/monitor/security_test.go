@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/security"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestCertificateOnFirstContact(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableSecurity = true
+	tm, dir := newTestMonitor(t, cfg)
+
+	validTo := cdp.TimeSinceEpoch(time.Now().Add(-48 * time.Hour))
+	details := &network.SecurityDetails{
+		Protocol:    "TLS 1.3",
+		Cipher:      "AES_128_GCM",
+		SubjectName: "staging.example.com",
+		Issuer:      "Example CA",
+		SanList:     []string{"staging.example.com", "*.staging.example.com"},
+		ValidTo:     &validTo,
+	}
+	for _, id := range []network.RequestID{"req-1", "req-2"} {
+		tm.handleEvent(&network.EventResponseReceived{
+			RequestID: id,
+			Response:  &network.Response{URL: "https://staging.example.com/" + id.String(), Status: 200, SecurityDetails: details},
+		})
+	}
+
+	var certs []map[string]interface{}
+	for _, ev := range readTestEvents(t, tm, dir) {
+		if ev["event_type"] == events.EventSecurityCertificate {
+			certs = append(certs, ev["data"].(map[string]interface{}))
+		}
+	}
+	if len(certs) != 1 {
+		t.Fatalf("expected 1 certificate event per origin, got %d", len(certs))
+	}
+
+	cert := certs[0]
+	if cert["origin"] != "https://staging.example.com" || cert["request_id"] != "req-1" {
+		t.Errorf("unexpected origin/request: %v", cert)
+	}
+	if cert["subject"] != "staging.example.com" || cert["issuer"] != "Example CA" || cert["protocol"] != "TLS 1.3" {
+		t.Errorf("unexpected certificate details: %v", cert)
+	}
+	if cert["expired"] != true || cert["expires_in_days"] != float64(-2) {
+		t.Errorf("expected expired certificate, got expired=%v expires_in_days=%v", cert["expired"], cert["expires_in_days"])
+	}
+}
+
+func TestSecurityStateChanged(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableSecurity = true
+	tm, dir := newTestMonitor(t, cfg)
+
+	secure := &security.VisibleSecurityState{SecurityState: security.StateSecure}
+	mixed := &security.VisibleSecurityState{
+		SecurityState:         security.StateNeutral,
+		SecurityStateIssueIDs: []string{"displayed-mixed-content"},
+	}
+	tm.handleEvent(&security.EventVisibleSecurityStateChanged{VisibleSecurityState: secure})
+	tm.handleEvent(&security.EventVisibleSecurityStateChanged{VisibleSecurityState: secure})
+	tm.handleEvent(&security.EventVisibleSecurityStateChanged{VisibleSecurityState: mixed})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events (unchanged state skipped), got %d", len(got))
+	}
+
+	data := got[1]["data"].(map[string]interface{})
+	if data["security_state"] != "neutral" || data["previous_state"] != "secure" {
+		t.Errorf("unexpected state change: %v", data)
+	}
+	if issues := data["issues"].([]interface{}); len(issues) != 1 || issues[0] != "displayed-mixed-content" {
+		t.Errorf("expected mixed content issue, got %v", data["issues"])
+	}
+}
+
+func TestSecurityDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableSecurity = false
+	tm, _ := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&security.EventVisibleSecurityStateChanged{
+		VisibleSecurityState: &security.VisibleSecurityState{SecurityState: security.StateInsecure},
+	})
+
+	if openTestFiles(tm) != 0 {
+		t.Error("expected no events to be written when security events are disabled")
+	}
+}
@@ -0,0 +1,236 @@
+package monitor
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/debugger"
+	cdpio "github.com/chromedp/cdproto/io"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+	"github.com/ajsharma/browser_tail/internal/sourcemap"
+)
+
+// Limits for fetching source maps through the page.
+const (
+	sourceMapFetchTimeout = 10 * time.Second
+	maxSourceMapSize      = 64 * 1024 * 1024
+)
+
+// scriptInfo records where a parsed script's source map lives.
+type scriptInfo struct {
+	URL          string
+	SourceMapURL string
+}
+
+// SetSourceMaps sets the cache used to symbolicate stack frames.
+// Monitors without a cache log frames as reported by Chrome.
+func (tm *TabMonitor) SetSourceMaps(cache *sourcemap.Cache) {
+	tm.mu.Lock()
+	tm.sourceMaps = cache
+	tm.mu.Unlock()
+}
+
+func (tm *TabMonitor) sourceMapCache() *sourcemap.Cache {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.sourceMaps
+}
+
+// enableDebugger enables the Debugger domain so scriptParsed events report
+// source map URLs. Pauses are skipped so `debugger;` statements and
+// breakpoints left in page code never stop execution.
+func enableDebugger(targetCtx context.Context) error {
+	return chromedp.Run(targetCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if _, err := debugger.Enable().Do(ctx); err != nil {
+			return err
+		}
+		return debugger.SetSkipAllPauses(true).Do(ctx)
+	}))
+}
+
+// trackScript remembers the source map URL of a parsed script.
+func (tm *TabMonitor) trackScript(ev *debugger.EventScriptParsed) {
+	if ev.SourceMapURL == "" {
+		return
+	}
+
+	tm.scriptMu.Lock()
+	tm.scripts[ev.ScriptID] = scriptInfo{URL: ev.URL, SourceMapURL: ev.SourceMapURL}
+	tm.scriptMu.Unlock()
+}
+
+// clearScripts forgets parsed scripts once their execution contexts are gone.
+func (tm *TabMonitor) clearScripts() {
+	tm.scriptMu.Lock()
+	tm.scripts = make(map[runtime.ScriptID]scriptInfo)
+	tm.scriptMu.Unlock()
+}
+
+// writeSymbolicated writes a console or error event after resolving
+// its stack frames through source maps. Resolving may fetch maps over CDP,
+// so it happens on the tab's output queue, in order with the tab's other
+// events; the event keeps its original timestamp.
+func (tm *TabMonitor) writeSymbolicated(event *events.LogEvent) {
+	cache := tm.sourceMapCache()
+	if cache == nil {
+		tm.writeEvent(event)
+		return
+	}
+
+	top := tm.topLevel(event)
+	top.enqueue(func() {
+		tm.symbolicate(cache, event)
+		top.writeToSink(event)
+	})
+}
+
+// symbolicate adds original positions to an event's stack and location.
+func (tm *TabMonitor) symbolicate(cache *sourcemap.Cache, event *events.LogEvent) {
+	switch data := event.Data.(type) {
+	case *events.ConsoleData:
+		tm.symbolicateStack(cache, data.Stack)
+	case *events.RuntimeErrorData:
+		tm.symbolicateStack(cache, data.Stack)
+		data.Original = tm.resolveOriginal(cache, data.ScriptID, data.URL, data.Line, data.Column)
+	case *events.UnhandledPromiseData:
+		tm.symbolicateStack(cache, data.Stack)
+		data.Original = tm.resolveOriginal(cache, data.ScriptID, data.URL, data.Line, data.Column)
+	}
+}
+
+// symbolicateStack adds original positions to frames in place.
+func (tm *TabMonitor) symbolicateStack(cache *sourcemap.Cache, stack []events.StackFrame) {
+	for i := range stack {
+		frame := &stack[i]
+		frame.Original = tm.resolveOriginal(cache, frame.ScriptID, frame.URL, frame.Line, frame.Column)
+	}
+}
+
+// resolveOriginal maps a generated script position to its original source
+// position. Returns nil if the script has no usable source map.
+func (tm *TabMonitor) resolveOriginal(cache *sourcemap.Cache, scriptID, scriptURL string, line, column int64) *events.OriginalPosition {
+	tm.scriptMu.RLock()
+	info, known := tm.scripts[runtime.ScriptID(scriptID)]
+	tm.scriptMu.RUnlock()
+
+	if scriptURL == "" {
+		scriptURL = info.URL
+	}
+	if scriptURL == "" {
+		return nil
+	}
+
+	var fetch sourcemap.Fetcher
+	if known {
+		fetch = func() ([]byte, error) {
+			return tm.fetchSourceMap(scriptURL, info.SourceMapURL)
+		}
+	}
+
+	m, err := cache.Get(scriptURL, fetch)
+	if err != nil {
+		return nil
+	}
+
+	pos, ok := m.Lookup(int(line), int(column))
+	if !ok {
+		return nil
+	}
+
+	return &events.OriginalPosition{
+		File:     pos.Source,
+		Line:     int64(pos.Line),
+		Column:   int64(pos.Column),
+		Function: pos.Name,
+	}
+}
+
+// fetchSourceMap loads a script's source map. Inline data: maps are decoded
+// directly; others are loaded through the page so its cookies and
+// credentials apply.
+func (tm *TabMonitor) fetchSourceMap(scriptURL, mapURL string) ([]byte, error) {
+	resolved, err := sourcemap.ResolveURL(scriptURL, mapURL)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(resolved, "data:") {
+		return sourcemap.DecodeDataURL(resolved)
+	}
+
+	tm.mu.RLock()
+	targetCtx := tm.targetCtx
+	tm.mu.RUnlock()
+
+	if targetCtx == nil {
+		return nil, fmt.Errorf("target not attached")
+	}
+
+	ctx, cancel := context.WithTimeout(targetCtx, sourceMapFetchTimeout)
+	defer cancel()
+
+	var data []byte
+	err = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		params := network.LoadNetworkResource(resolved, &network.LoadNetworkResourceOptions{IncludeCredentials: true})
+		// Frame targets require a frame ID; worker targets must omit it
+		if tm.origin.WorkerID == "" {
+			params = params.WithFrameID(cdp.FrameID(tm.targetID))
+		}
+
+		res, err := params.Do(ctx)
+		if err != nil {
+			return err
+		}
+		if !res.Success {
+			if res.HTTPStatusCode == 404 || res.HTTPStatusCode == 410 {
+				return fmt.Errorf("%w: %s (status %.0f)", sourcemap.ErrNotFound, resolved, res.HTTPStatusCode)
+			}
+			return fmt.Errorf("failed to load %s: %s (status %.0f)", resolved, res.NetErrorName, res.HTTPStatusCode)
+		}
+		defer func() {
+			_ = cdpio.Close(res.Stream).Do(ctx)
+		}()
+
+		data, err = readStream(ctx, res.Stream)
+		return err
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readStream reads an IO stream to EOF, up to maxSourceMapSize bytes.
+func readStream(ctx context.Context, handle cdpio.StreamHandle) ([]byte, error) {
+	var data []byte
+	for {
+		var res cdpio.ReadReturns
+		if err := cdp.Execute(ctx, cdpio.CommandRead, cdpio.Read(handle), &res); err != nil {
+			return nil, err
+		}
+
+		chunk := []byte(res.Data)
+		if res.Base64encoded {
+			decoded, err := base64.StdEncoding.DecodeString(res.Data)
+			if err != nil {
+				return nil, err
+			}
+			chunk = decoded
+		}
+
+		data = append(data, chunk...)
+		if len(data) > maxSourceMapSize {
+			return nil, fmt.Errorf("source map exceeds %d bytes", maxSourceMapSize)
+		}
+		if res.EOF {
+			return data, nil
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chromedp/cdproto/debugger"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+	"github.com/ajsharma/browser_tail/internal/sourcemap"
+)
+
+func TestSymbolicateStack(t *testing.T) {
+	dir := t.TempDir()
+	sourceMap := `{"version":3,"sources":["src/app.ts"],"names":["onClick"],"mappings":"AAAA,UAAUA"}`
+	if err := os.WriteFile(filepath.Join(dir, "main.js.map"), []byte(sourceMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tm, _ := newTestMonitor(t, config.DefaultConfig())
+	cache := sourcemap.NewCache(dir)
+
+	stack := []events.StackFrame{
+		{Function: "a", URL: "https://example.com/main.js", Line: 0, Column: 12},
+		{Function: "b", URL: "https://example.com/unmapped.js", Line: 0, Column: 0},
+	}
+	tm.symbolicateStack(cache, stack)
+
+	original := stack[0].Original
+	if original == nil {
+		t.Fatal("expected first frame to be symbolicated")
+	}
+	if original.File != "src/app.ts" || original.Column != 10 || original.Function != "onClick" {
+		t.Errorf("unexpected original position: %+v", original)
+	}
+	if stack[1].Original != nil {
+		t.Errorf("expected frame without a map to be left alone, got %+v", stack[1].Original)
+	}
+}
+
+func TestTrackScript(t *testing.T) {
+	tm, _ := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&debugger.EventScriptParsed{ScriptID: "1", URL: "https://example.com/main.js", SourceMapURL: "main.js.map"})
+	tm.handleEvent(&debugger.EventScriptParsed{ScriptID: "2", URL: "https://example.com/plain.js"})
+
+	if len(tm.scripts) != 1 {
+		t.Fatalf("expected only scripts with source maps to be tracked, got %d", len(tm.scripts))
+	}
+	if tm.scripts["1"].SourceMapURL != "main.js.map" {
+		t.Errorf("unexpected script info: %+v", tm.scripts["1"])
+	}
+
+	tm.clearScripts()
+	if len(tm.scripts) != 0 {
+		t.Errorf("expected scripts to be cleared, got %d", len(tm.scripts))
+	}
+}
+
+func TestSymbolicatedEventsKeepOrder(t *testing.T) {
+	mapDir := t.TempDir()
+	sourceMap := `{"version":3,"sources":["src/app.ts"],"names":["onClick"],"mappings":"AAAA,UAAUA"}`
+	if err := os.WriteFile(filepath.Join(mapDir, "main.js.map"), []byte(sourceMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+	tm.SetSourceMaps(sourcemap.NewCache(mapDir))
+
+	tm.writeSymbolicated(events.NewLogEvent("example.com", "tab-1", events.EventConsoleError, &events.ConsoleData{
+		Stack: []events.StackFrame{{URL: "https://example.com/main.js", Line: 0, Column: 12}},
+	}))
+	tm.writeEvent(events.NewPageLoadEvent("example.com", "tab-1", "https://example.com"))
+	tm.Stop()
+	tm.Wait()
+
+	// Events after the tab has closed must not reopen its log
+	tm.writeSymbolicated(events.NewLogEvent("example.com", "tab-1", events.EventConsoleLog, &events.ConsoleData{}))
+	tm.Wait()
+	if n := openTestFiles(tm); n != 0 {
+		t.Errorf("expected the log to stay closed, got %d open files", n)
+	}
+
+	got := readTestEvents(t, tm, dir)
+	wantTypes := []string{events.EventConsoleError, events.EventPageLoad, events.EventMetaTabClosed}
+	if len(got) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %d", len(wantTypes), len(got))
+	}
+	for i, want := range wantTypes {
+		if got[i]["event_type"] != want {
+			t.Errorf("event %d: expected %s, got %v", i, want, got[i]["event_type"])
+		}
+	}
+
+	stack := got[0]["data"].(map[string]interface{})["stack"].([]interface{})
+	if stack[0].(map[string]interface{})["original"] == nil {
+		t.Error("expected the queued event to be symbolicated")
+	}
+}
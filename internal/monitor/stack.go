@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/runtime"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// convertStackTrace flattens a CDP stack trace and its async parents into
+// a single list of frames. The first frame after each async boundary carries
+// the boundary's description (e.g. "Promise.then", "setTimeout").
+func convertStackTrace(st *runtime.StackTrace) []events.StackFrame {
+	if st == nil {
+		return nil
+	}
+
+	var frames []events.StackFrame
+	asyncBoundary := ""
+	for trace := st; trace != nil; trace = trace.Parent {
+		if trace != st {
+			asyncBoundary = trace.Description
+			if asyncBoundary == "" {
+				asyncBoundary = "async"
+			}
+		}
+		for _, cf := range trace.CallFrames {
+			if cf == nil {
+				continue
+			}
+			frames = append(frames, events.StackFrame{
+				Function:      cf.FunctionName,
+				URL:           cf.URL,
+				Line:          cf.LineNumber,
+				Column:        cf.ColumnNumber,
+				ScriptID:      string(cf.ScriptID),
+				AsyncBoundary: asyncBoundary,
+			})
+			asyncBoundary = ""
+		}
+	}
+	return frames
+}
+
+// exceptionClassAndMessage extracts the class name and message of a thrown
+// value. Error objects report "ClassName: message" as the first line of their
+// description; other thrown values use their primitive value or description.
+func exceptionClassAndMessage(obj *runtime.RemoteObject) (string, string) {
+	if obj == nil {
+		return "", ""
+	}
+
+	if obj.Subtype == runtime.SubtypeError {
+		message, _, _ := strings.Cut(obj.Description, "\n")
+		if obj.ClassName != "" {
+			message = strings.TrimPrefix(message, obj.ClassName+": ")
+			// Errors thrown without a message describe themselves by class alone
+			if message == obj.ClassName {
+				message = ""
+			}
+		}
+		return obj.ClassName, message
+	}
+
+	value := extractRemoteObjectValue(obj)
+	if s, ok := value.(string); ok {
+		return obj.ClassName, s
+	}
+	if value == nil {
+		return obj.ClassName, "null"
+	}
+	if obj.Description != "" {
+		return obj.ClassName, obj.Description
+	}
+	return obj.ClassName, fmt.Sprint(value)
+}
@@ -0,0 +1,128 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/runtime"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+)
+
+func TestConvertStackTrace(t *testing.T) {
+	st := &runtime.StackTrace{
+		CallFrames: []*runtime.CallFrame{
+			{FunctionName: "inner", URL: "https://example.com/app.js", LineNumber: 10, ColumnNumber: 4},
+			{FunctionName: "outer", URL: "https://example.com/app.js", LineNumber: 20, ColumnNumber: 2},
+		},
+		Parent: &runtime.StackTrace{
+			Description: "setTimeout",
+			CallFrames: []*runtime.CallFrame{
+				{FunctionName: "schedule", URL: "https://example.com/app.js", LineNumber: 30},
+			},
+		},
+	}
+
+	frames := convertStackTrace(st)
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+	if frames[0].Function != "inner" || frames[0].Line != 10 || frames[0].Column != 4 {
+		t.Errorf("unexpected first frame: %+v", frames[0])
+	}
+	if frames[1].AsyncBoundary != "" {
+		t.Errorf("expected no async boundary on synchronous frame, got %q", frames[1].AsyncBoundary)
+	}
+	if frames[2].AsyncBoundary != "setTimeout" {
+		t.Errorf("expected async boundary setTimeout, got %q", frames[2].AsyncBoundary)
+	}
+
+	if convertStackTrace(nil) != nil {
+		t.Error("expected nil frames for nil stack trace")
+	}
+}
+
+func TestExceptionClassAndMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		obj         *runtime.RemoteObject
+		wantClass   string
+		wantMessage string
+	}{
+		{
+			name: "error object",
+			obj: &runtime.RemoteObject{
+				Type:        runtime.TypeObject,
+				Subtype:     runtime.SubtypeError,
+				ClassName:   "TypeError",
+				Description: "TypeError: x is undefined\n    at foo (app.js:1:2)",
+			},
+			wantClass:   "TypeError",
+			wantMessage: "x is undefined",
+		},
+		{
+			name: "error without message",
+			obj: &runtime.RemoteObject{
+				Type:        runtime.TypeObject,
+				Subtype:     runtime.SubtypeError,
+				ClassName:   "Error",
+				Description: "Error\n    at foo (app.js:1:2)",
+			},
+			wantClass:   "Error",
+			wantMessage: "",
+		},
+		{
+			name:        "thrown string",
+			obj:         &runtime.RemoteObject{Type: runtime.TypeString, Value: []byte(`"boom"`)},
+			wantClass:   "",
+			wantMessage: "boom",
+		},
+		{
+			name:        "nil object",
+			obj:         nil,
+			wantClass:   "",
+			wantMessage: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, message := exceptionClassAndMessage(tt.obj)
+			if class != tt.wantClass || message != tt.wantMessage {
+				t.Errorf("got (%q, %q), want (%q, %q)", class, message, tt.wantClass, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestRuntimeErrorIncludesStack(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&runtime.EventExceptionThrown{ExceptionDetails: &runtime.ExceptionDetails{
+		Text: "Uncaught",
+		Exception: &runtime.RemoteObject{
+			Type:        runtime.TypeObject,
+			Subtype:     runtime.SubtypeError,
+			ClassName:   "RangeError",
+			Description: "RangeError: out of range",
+		},
+		StackTrace: &runtime.StackTrace{CallFrames: []*runtime.CallFrame{
+			{FunctionName: "f", URL: "https://example.com/app.js", LineNumber: 1, ColumnNumber: 2},
+		}},
+	}})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(got))
+	}
+	data := got[0]["data"].(map[string]interface{})
+	if data["exception_class"] != "RangeError" || data["exception_message"] != "out of range" {
+		t.Errorf("unexpected exception fields: %v", data)
+	}
+	stack, ok := data["stack"].([]interface{})
+	if !ok || len(stack) != 1 {
+		t.Fatalf("expected 1 stack frame, got %v", data["stack"])
+	}
+	if frame := stack[0].(map[string]interface{}); frame["function"] != "f" {
+		t.Errorf("expected function f, got %v", frame["function"])
+	}
+}
@@ -0,0 +1,305 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/domstorage"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/chromedp"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+	"github.com/ajsharma/browser_tail/internal/redact"
+)
+
+// Storage types reported in storage.item_* events.
+const (
+	StorageTypeLocal   = "local"
+	StorageTypeSession = "session"
+)
+
+// enableStorage enables localStorage and sessionStorage change events.
+func enableStorage(targetCtx context.Context) error {
+	return chromedp.Run(targetCtx, domstorage.Enable())
+}
+
+// handleStorageNavigation starts watching the origin a tab's main frame
+// navigated to.
+func (tm *TabMonitor) handleStorageNavigation(ev *page.EventFrameNavigated) {
+	if ev.Frame == nil || ev.Frame.ParentID != "" || tm.origin.FrameID != "" {
+		return
+	}
+	// Run in a goroutine: CDP commands cannot be run from an event listener
+	go tm.watchStorageOrigin(ev.Frame.URL)
+}
+
+// watchStorageOrigin subscribes to IndexedDB and Cache Storage changes for
+// the origin of pageURL and writes a storage.snapshot of its cookies and DOM
+// storage. Each origin is only watched once per tab.
+func (tm *TabMonitor) watchStorageOrigin(pageURL string) {
+	origin := httpOrigin(pageURL)
+	if origin == "" {
+		return
+	}
+
+	tm.mu.Lock()
+	tCtx := tm.targetCtx
+	_, watched := tm.storageOrigins[origin]
+	if tCtx != nil && !watched {
+		tm.storageOrigins[origin] = struct{}{}
+	}
+	tm.mu.Unlock()
+
+	if tCtx == nil || watched {
+		return
+	}
+
+	err := chromedp.Run(tCtx,
+		storage.TrackIndexedDBForOrigin(origin),
+		storage.TrackCacheStorageForOrigin(origin),
+	)
+	if err != nil {
+		log.Printf("Warning: failed to track storage (tab %s, origin %s): %v", tm.tabID, origin, err)
+	}
+
+	snapshot := &events.StorageSnapshotData{
+		Origin:         origin,
+		Cookies:        []events.StorageCookie{},
+		LocalStorage:   map[string]string{},
+		SessionStorage: map[string]string{},
+	}
+	_ = chromedp.Run(tCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		// Each part is best effort: a page without storage access still
+		// gets a snapshot of what could be read
+		if cookies, err := network.GetCookies().WithURLs([]string{pageURL}).Do(ctx); err == nil {
+			for _, c := range cookies {
+				snapshot.Cookies = append(snapshot.Cookies, tm.convertCookie(c))
+			}
+		}
+		tm.readDOMStorage(ctx, origin, true, snapshot.LocalStorage)
+		tm.readDOMStorage(ctx, origin, false, snapshot.SessionStorage)
+		return nil
+	}))
+
+	site, tabID := tm.siteAndTab()
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageSnapshot, snapshot))
+}
+
+// readDOMStorage copies an origin's localStorage or sessionStorage into items.
+func (tm *TabMonitor) readDOMStorage(ctx context.Context, origin string, local bool, items map[string]string) {
+	entries, err := domstorage.GetDOMStorageItems(&domstorage.StorageID{
+		SecurityOrigin: origin,
+		IsLocalStorage: local,
+	}).Do(ctx)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if len(entry) == 2 {
+			items[entry[0]] = tm.redactor.RedactValue(entry[1])
+		}
+	}
+}
+
+// handleDOMStorageEvent logs a localStorage or sessionStorage change.
+func (tm *TabMonitor) handleDOMStorageEvent(ev interface{}, site, tabID string) {
+	switch ev := ev.(type) {
+	case *domstorage.EventDomStorageItemAdded:
+		storageType, origin := storageIDInfo(ev.StorageID)
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageItemAdded, &events.StorageItemData{
+			StorageType: storageType,
+			Origin:      origin,
+			Key:         ev.Key,
+			Value:       tm.redactor.RedactValue(ev.NewValue),
+		}))
+
+	case *domstorage.EventDomStorageItemUpdated:
+		storageType, origin := storageIDInfo(ev.StorageID)
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageItemUpdated, &events.StorageItemData{
+			StorageType: storageType,
+			Origin:      origin,
+			Key:         ev.Key,
+			Value:       tm.redactor.RedactValue(ev.NewValue),
+			OldValue:    tm.redactor.RedactValue(ev.OldValue),
+		}))
+
+	case *domstorage.EventDomStorageItemRemoved:
+		storageType, origin := storageIDInfo(ev.StorageID)
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageItemRemoved, &events.StorageItemData{
+			StorageType: storageType,
+			Origin:      origin,
+			Key:         ev.Key,
+		}))
+
+	case *domstorage.EventDomStorageItemsCleared:
+		storageType, origin := storageIDInfo(ev.StorageID)
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageCleared, &events.StorageClearedData{
+			StorageType: storageType,
+			Origin:      origin,
+		}))
+	}
+}
+
+// handleStorageDomainEvent logs IndexedDB and Cache Storage changes for
+// watched origins.
+func (tm *TabMonitor) handleStorageDomainEvent(ev interface{}, site, tabID string) {
+	switch ev := ev.(type) {
+	case *storage.EventIndexedDBContentUpdated:
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageIndexedDB, &events.StorageIndexedDBData{
+			Origin:      ev.Origin,
+			Database:    ev.DatabaseName,
+			ObjectStore: ev.ObjectStoreName,
+		}))
+
+	case *storage.EventIndexedDBListUpdated:
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageIndexedDB, &events.StorageIndexedDBData{
+			Origin: ev.Origin,
+		}))
+
+	case *storage.EventCacheStorageContentUpdated:
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageCacheStorage, &events.StorageCacheData{
+			Origin:    ev.Origin,
+			CacheName: ev.CacheName,
+		}))
+
+	case *storage.EventCacheStorageListUpdated:
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageCacheStorage, &events.StorageCacheData{
+			Origin: ev.Origin,
+		}))
+	}
+}
+
+// handleSetCookies logs each Set-Cookie line of a response, including
+// cookies the browser refused to store.
+func (tm *TabMonitor) handleSetCookies(ev *network.EventResponseReceivedExtraInfo, site, tabID string) {
+	var lines []string
+	for name, value := range ev.Headers {
+		if s, ok := value.(string); ok && strings.EqualFold(name, "set-cookie") {
+			lines = append(lines, strings.Split(s, "\n")...)
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	blocked := make(map[string][]string, len(ev.BlockedCookies))
+	for _, bc := range ev.BlockedCookies {
+		if bc == nil {
+			continue
+		}
+		reasons := make([]string, 0, len(bc.BlockedReasons))
+		for _, r := range bc.BlockedReasons {
+			reasons = append(reasons, r.String())
+		}
+		blocked[bc.CookieLine] = reasons
+	}
+
+	tm.trackerMu.RLock()
+	var requestURL string
+	if rt, exists := tm.requestTimings[ev.RequestID]; exists {
+		requestURL = rt.URL
+	}
+	tm.trackerMu.RUnlock()
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		data := &events.CookieSetData{
+			RequestID: ev.RequestID.String(),
+			URL:       requestURL,
+		}
+		if reasons, isBlocked := blocked[line]; isBlocked {
+			data.Blocked = true
+			data.BlockedReasons = reasons
+		}
+
+		if c, err := http.ParseSetCookie(line); err == nil {
+			data.StorageCookie = tm.convertSetCookie(c)
+		} else if tm.redactor.IsEnabled() {
+			data.RawCookie = redact.RedactedValue
+		} else {
+			data.RawCookie = line
+		}
+
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventStorageCookieSet, data))
+	}
+}
+
+// convertSetCookie converts a parsed Set-Cookie line, redacting its value.
+func (tm *TabMonitor) convertSetCookie(c *http.Cookie) events.StorageCookie {
+	sc := events.StorageCookie{
+		Name:        c.Name,
+		Value:       tm.redactor.RedactValue(c.Value),
+		Domain:      c.Domain,
+		Path:        c.Path,
+		MaxAge:      c.MaxAge,
+		Secure:      c.Secure,
+		HTTPOnly:    c.HttpOnly,
+		Partitioned: c.Partitioned,
+	}
+	if !c.Expires.IsZero() {
+		sc.Expires = c.Expires.UTC().Format(time.RFC3339)
+	}
+	switch c.SameSite {
+	case http.SameSiteLaxMode:
+		sc.SameSite = "Lax"
+	case http.SameSiteStrictMode:
+		sc.SameSite = "Strict"
+	case http.SameSiteNoneMode:
+		sc.SameSite = "None"
+	}
+	return sc
+}
+
+// convertCookie converts a cookie from the browser's cookie store,
+// redacting its value.
+func (tm *TabMonitor) convertCookie(c *network.Cookie) events.StorageCookie {
+	sc := events.StorageCookie{
+		Name:        c.Name,
+		Value:       tm.redactor.RedactValue(c.Value),
+		Domain:      c.Domain,
+		Path:        c.Path,
+		Secure:      c.Secure,
+		HTTPOnly:    c.HTTPOnly,
+		SameSite:    c.SameSite.String(),
+		Partitioned: c.PartitionKey != nil,
+	}
+	if !c.Session && c.Expires > 0 {
+		sc.Expires = time.Unix(int64(c.Expires), 0).UTC().Format(time.RFC3339)
+	}
+	return sc
+}
+
+// storageIDInfo returns the storage type and origin of a DOM storage area.
+func storageIDInfo(id *domstorage.StorageID) (string, string) {
+	if id == nil {
+		return "", ""
+	}
+	storageType := StorageTypeSession
+	if id.IsLocalStorage {
+		storageType = StorageTypeLocal
+	}
+	origin := id.SecurityOrigin
+	if origin == "" {
+		origin = strings.TrimSuffix(string(id.StorageKey), "/")
+	}
+	return storageType, origin
+}
+
+// httpOrigin returns the origin of an http(s) URL, or "" for other URLs.
+func httpOrigin(pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
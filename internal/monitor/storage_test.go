@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chromedp/cdproto/domstorage"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestSetCookieEvents(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableStorage = true
+	tm, dir := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&network.EventRequestWillBeSent{
+		RequestID: "req-1",
+		Request:   &network.Request{URL: "https://example.com/login", Method: "POST"},
+	})
+	tm.handleEvent(&network.EventResponseReceivedExtraInfo{
+		RequestID: "req-1",
+		Headers: network.Headers{
+			"set-cookie": "sid=abc123; Path=/; Secure; HttpOnly; SameSite=Lax\ntracker=xyz; Domain=ads.example.net",
+		},
+		BlockedCookies: []*network.BlockedSetCookieWithReason{{
+			CookieLine:     "tracker=xyz; Domain=ads.example.net",
+			BlockedReasons: []network.SetCookieBlockedReason{network.SetCookieBlockedReasonInvalidDomain},
+		}},
+	})
+
+	var cookies []map[string]interface{}
+	for _, ev := range readTestEvents(t, tm, dir) {
+		if ev["event_type"] == events.EventStorageCookieSet {
+			cookies = append(cookies, ev["data"].(map[string]interface{}))
+		}
+	}
+	if len(cookies) != 2 {
+		t.Fatalf("expected 2 cookie events, got %d", len(cookies))
+	}
+
+	sid := cookies[0]
+	if sid["name"] != "sid" || sid["url"] != "https://example.com/login" || sid["http_only"] != true || sid["same_site"] != "Lax" {
+		t.Errorf("unexpected cookie data: %v", sid)
+	}
+	if value := sid["value"].(string); strings.Contains(value, "abc123") || !strings.Contains(value, "sha256:") {
+		t.Errorf("expected cookie value to be fingerprinted, got %q", value)
+	}
+	if _, ok := sid["blocked"]; ok {
+		t.Errorf("expected stored cookie not to be blocked, got %v", sid["blocked"])
+	}
+
+	tracker := cookies[1]
+	if tracker["blocked"] != true {
+		t.Errorf("expected blocked cookie, got %v", tracker)
+	}
+	if reasons := tracker["blocked_reasons"].([]interface{}); len(reasons) != 1 || reasons[0] != "InvalidDomain" {
+		t.Errorf("unexpected blocked reasons: %v", reasons)
+	}
+}
+
+func TestDOMStorageEvents(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableStorage = true
+	tm, dir := newTestMonitor(t, cfg)
+	local := &domstorage.StorageID{SecurityOrigin: "https://example.com", IsLocalStorage: true}
+	session := &domstorage.StorageID{SecurityOrigin: "https://example.com"}
+
+	tm.handleEvent(&domstorage.EventDomStorageItemAdded{StorageID: local, Key: "theme", NewValue: "dark"})
+	tm.handleEvent(&domstorage.EventDomStorageItemUpdated{StorageID: local, Key: "authToken", OldValue: "old", NewValue: "new"})
+	tm.handleEvent(&domstorage.EventDomStorageItemRemoved{StorageID: session, Key: "cart"})
+	tm.handleEvent(&domstorage.EventDomStorageItemsCleared{StorageID: session})
+	tm.handleEvent(&storage.EventIndexedDBContentUpdated{Origin: "https://example.com", DatabaseName: "app", ObjectStoreName: "users"})
+
+	got := readTestEvents(t, tm, dir)
+	wantTypes := []string{
+		events.EventStorageItemAdded,
+		events.EventStorageItemUpdated,
+		events.EventStorageItemRemoved,
+		events.EventStorageCleared,
+		events.EventStorageIndexedDB,
+	}
+	if len(got) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %d", len(wantTypes), len(got))
+	}
+	for i, want := range wantTypes {
+		if got[i]["event_type"] != want {
+			t.Errorf("event %d: expected %s, got %v", i, want, got[i]["event_type"])
+		}
+	}
+
+	added := got[0]["data"].(map[string]interface{})
+	if added["storage_type"] != StorageTypeLocal || added["origin"] != "https://example.com" {
+		t.Errorf("unexpected item_added data: %v", added)
+	}
+	if value := added["value"].(string); value == "dark" || !strings.Contains(value, "sha256:") {
+		t.Errorf("expected value to be fingerprinted, got %q", value)
+	}
+	updated := got[1]["data"].(map[string]interface{})
+	value, oldValue := updated["value"].(string), updated["old_value"].(string)
+	if value == "new" || oldValue == "old" || value == oldValue {
+		t.Errorf("expected distinct fingerprints for old and new values, got %v", updated)
+	}
+	if removed := got[2]["data"].(map[string]interface{}); removed["storage_type"] != StorageTypeSession {
+		t.Errorf("expected session storage, got %v", removed)
+	}
+	if idb := got[4]["data"].(map[string]interface{}); idb["database"] != "app" || idb["object_store"] != "users" {
+		t.Errorf("unexpected indexeddb data: %v", idb)
+	}
+}
+
+func TestStorageDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableStorage = false
+	tm, _ := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&domstorage.EventDomStorageItemAdded{StorageID: &domstorage.StorageID{IsLocalStorage: true}, Key: "k", NewValue: "v"})
+	tm.handleEvent(&network.EventResponseReceivedExtraInfo{RequestID: "req-1", Headers: network.Headers{"Set-Cookie": "a=b"}})
+
+	if openTestFiles(tm) != 0 {
+		t.Error("expected no events to be written when storage events are disabled")
+	}
+}
+
+func TestHTTPOrigin(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/path?q=1": "https://example.com",
+		"http://localhost:3000/":       "http://localhost:3000",
+		"about:blank":                  "",
+		"chrome://settings":            "",
+	}
+	for input, want := range tests {
+		if got := httpOrigin(input); got != want {
+			t.Errorf("httpOrigin(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -3,15 +3,23 @@ package monitor
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"log"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/chromedp/cdproto/audits"
+	"github.com/chromedp/cdproto/debugger"
+	"github.com/chromedp/cdproto/domstorage"
+	cdplog "github.com/chromedp/cdproto/log"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/security"
+	"github.com/chromedp/cdproto/storage"
 	"github.com/chromedp/cdproto/target"
 	"github.com/chromedp/chromedp"
 
@@ -19,6 +27,7 @@ import (
 	"github.com/ajsharma/browser_tail/internal/events"
 	"github.com/ajsharma/browser_tail/internal/logger"
 	"github.com/ajsharma/browser_tail/internal/redact"
+	"github.com/ajsharma/browser_tail/internal/sourcemap"
 )
 
 // responseInfo stores response metadata for body capture.
@@ -39,27 +48,72 @@ type TabMonitor struct {
 	sessionID   string
 	startTime   time.Time
 
-	fileManager *logger.FileManager
-	config      *config.Config
-	redactor    *redact.Redactor
+	sink     logger.Sink
+	config   *config.Config
+	redactor *redact.Redactor
 
 	// Request tracking for body capture.
 	requestTracker map[network.RequestID]*responseInfo
+	requestTimings map[network.RequestID]*requestTiming
+	eventSources   map[network.RequestID]string // requestID -> URL
 	trackerMu      sync.RWMutex
 
-	// Target context for CDP commands.
-	targetCtx context.Context
-
-	ctx    context.Context
-	cancel context.CancelFunc
-	mu     sync.RWMutex
+	// Open WebSockets for frame correlation.
+	webSockets map[network.RequestID]*webSocketInfo
+	wsMu       sync.RWMutex
+
+	// Source maps for stack frame symbolication.
+	sourceMaps *sourcemap.Cache
+	scripts    map[runtime.ScriptID]scriptInfo
+	scriptMu   sync.RWMutex
+
+	// Ordered sink writes, started once an event needs symbolicating.
+	out       *outputQueue
+	outClosed bool
+	outMu     sync.Mutex
+
+	// Main frame navigation state.
+	mainFrameID string
+	pendingNav  pendingNavigation
+	dialog      *openDialog
+	frames      map[string]struct{} // same-process child frame IDs
+
+	// Downloads in progress, by GUID.
+	downloads  map[string]*downloadInfo
+	downloadMu sync.Mutex
+
+	// Nesting depth of open console.group blocks.
+	consoleDepth int
+
+	// Origins whose storage is being watched.
+	storageOrigins map[string]struct{}
+
+	// Origins whose certificate has been logged, and the last security state.
+	certOrigins   map[string]struct{}
+	securityState *security.VisibleSecurityState
+
+	// Worker and frame targets. A monitor with a parent logs into the parent's tab log.
+	parent   *TabMonitor
+	origin   eventOrigin
+	attached bool
+	children map[target.SessionID]*TabMonitor
+	childMu  sync.Mutex
+
+	// Target and browser contexts for CDP commands.
+	targetCtx  context.Context
+	browserCtx context.Context
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopOnce sync.Once
+	mu       sync.RWMutex
 }
 
 // NewTabMonitor creates a new tab monitor.
 func NewTabMonitor(
 	parentCtx context.Context,
 	targetID, tabID, site, title, url, sessionID string,
-	fm *logger.FileManager,
+	sink logger.Sink,
 	cfg *config.Config,
 ) *TabMonitor {
 	ctx, cancel := context.WithCancel(parentCtx)
@@ -72,10 +126,19 @@ func NewTabMonitor(
 		title:          title,
 		sessionID:      sessionID,
 		startTime:      time.Now(),
-		fileManager:    fm,
+		sink:           sink,
 		config:         cfg,
 		redactor:       redact.New(cfg.Redact),
 		requestTracker: make(map[network.RequestID]*responseInfo),
+		requestTimings: make(map[network.RequestID]*requestTiming),
+		eventSources:   make(map[network.RequestID]string),
+		webSockets:     make(map[network.RequestID]*webSocketInfo),
+		scripts:        make(map[runtime.ScriptID]scriptInfo),
+		frames:         make(map[string]struct{}),
+		downloads:      make(map[string]*downloadInfo),
+		storageOrigins: make(map[string]struct{}),
+		certOrigins:    make(map[string]struct{}),
+		children:       make(map[target.SessionID]*TabMonitor),
 		ctx:            ctx,
 		cancel:         cancel,
 	}
@@ -83,15 +146,21 @@ func NewTabMonitor(
 
 // Start begins monitoring the tab.
 func (tm *TabMonitor) Start(browserCtx context.Context) error {
+	if tm.origin.isChild() {
+		return tm.startChild(browserCtx)
+	}
+
 	// Create chromedp.Context for this specific target
 	targetCtx, cancel := chromedp.NewContext(browserCtx,
 		chromedp.WithTargetID(target.ID(tm.targetID)),
 	)
 	defer cancel()
 
-	// Store targetCtx for body capture (synchronized for goroutine access)
+	// Store contexts for body capture and worker attachment
+	// (synchronized for goroutine access)
 	tm.mu.Lock()
 	tm.targetCtx = targetCtx
+	tm.browserCtx = browserCtx
 	tm.mu.Unlock()
 
 	// Enable required CDP domains
@@ -109,6 +178,9 @@ func (tm *TabMonitor) Start(browserCtx context.Context) error {
 		}
 	}
 
+	// Enable optional domains
+	tm.enableOptionalDomains(targetCtx)
+
 	// Write tab created event
 	tm.writeEvent(events.NewTabCreatedEvent(
 		tm.currentSite,
@@ -126,21 +198,19 @@ func (tm *TabMonitor) Start(browserCtx context.Context) error {
 		tm.handleEvent(ev)
 	})
 
+	// Snapshot the storage of the page the tab is already showing. The
+	// listener is attached first so changes made meanwhile are not lost.
+	if tm.config.EnableStorage {
+		tm.watchStorageOrigin(tm.CurrentURL())
+	}
+
 	// Start periodic cleanup of expired request tracker entries
-	go func() {
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-tm.ctx.Done():
-				return
-			case <-targetCtx.Done():
-				return
-			case <-ticker.C:
-				tm.cleanExpiredRequests(60 * time.Second)
-			}
-		}
-	}()
+	go tm.runRequestCleanup(targetCtx)
+
+	// Start periodic performance metrics sampling if configured
+	if tm.config.PerfMetricsInterval > 0 {
+		go tm.runPerfMetrics(targetCtx, tm.config.PerfMetricsInterval)
+	}
 
 	// Wait for context cancellation
 	select {
@@ -151,33 +221,96 @@ func (tm *TabMonitor) Start(browserCtx context.Context) error {
 	return nil
 }
 
+// optionalDomain is a CDP domain or injected script a tab can be monitored
+// without.
+type optionalDomain struct {
+	name    string
+	enabled bool
+	enable  func(context.Context) error
+}
+
+// enableOptionalDomains enables the configured optional domains. A failure
+// only loses that domain's events, so it is logged and monitoring goes on.
+func (tm *TabMonitor) enableOptionalDomains(targetCtx context.Context) {
+	cfg := tm.config
+	domains := []optionalDomain{
+		{"browser log", cfg.EnableBrowserLog, enableBrowserLog},
+		{"audits", cfg.EnableAudits, enableAudits},
+		{"source maps", cfg.EnableSourceMaps && tm.sourceMapCache() != nil, enableDebugger},
+		{"web vitals", cfg.EnableWebVitals, enableWebVitals},
+		{"long tasks", cfg.EnableLongTasks, enableLongTasks},
+		{"security", cfg.EnableSecurity, enableSecurity},
+		{"storage", cfg.EnableStorage, enableStorage},
+	}
+
+	for _, d := range domains {
+		if !d.enabled {
+			continue
+		}
+		if err := d.enable(targetCtx); err != nil {
+			log.Printf("Warning: failed to enable %s (tab %s): %v", d.name, tm.tabID, err)
+		}
+	}
+}
+
+// runRequestCleanup periodically removes expired request tracker entries
+// until the monitor or its target goes away.
+func (tm *TabMonitor) runRequestCleanup(targetCtx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tm.ctx.Done():
+			return
+		case <-targetCtx.Done():
+			return
+		case <-ticker.C:
+			tm.cleanExpiredRequests(60 * time.Second)
+			tm.cleanOrphanedTimings(orphanedTimingAge)
+		}
+	}
+}
+
+// siteAndTab returns the site and tab ID events should be logged under.
+// Child monitors log under their parent tab.
+func (tm *TabMonitor) siteAndTab() (string, string) {
+	if tm.parent != nil {
+		return tm.parent.siteAndTab()
+	}
+
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.currentSite, tm.tabID
+}
+
 // handleEvent processes CDP events.
 func (tm *TabMonitor) handleEvent(ev interface{}) {
-	tm.mu.RLock()
-	site := tm.currentSite
-	tabID := tm.tabID
+	site, tabID := tm.siteAndTab()
 	cfg := tm.config
-	tm.mu.RUnlock()
 
 	switch ev := ev.(type) {
 	// Page events
-	case *page.EventFrameNavigated:
-		if cfg.EnablePage && ev.Frame.ParentID == "" { // Main frame only
-			tm.mu.Lock()
-			tm.currentURL = ev.Frame.URL
-			tm.mu.Unlock()
-
-			tm.writeEvent(events.NewPageNavigateEvent(
-				site,
-				tabID,
-				ev.Frame.URL,
-				"", // referrer not available in this event
-				"navigation",
-			))
+	case *page.EventFrameNavigated,
+		*page.EventFrameStartedNavigating,
+		*page.EventNavigatedWithinDocument,
+		*page.EventFrameAttached,
+		*page.EventFrameDetached:
+		tm.trackFrame(ev)
+		if nav, ok := ev.(*page.EventFrameNavigated); ok && cfg.EnableStorage {
+			tm.handleStorageNavigation(nav)
 		}
+		if cfg.EnablePage {
+			tm.handleFrameEvent(ev, site, tabID)
+		}
+
+	case *page.EventJavascriptDialogOpening:
+		tm.handleDialogOpening(ev, site, tabID)
+
+	case *page.EventJavascriptDialogClosed:
+		tm.handleDialogClosed(ev, site, tabID)
 
 	case *page.EventLoadEventFired:
-		if cfg.EnablePage {
+		if cfg.EnablePage && tm.origin.FrameID == "" {
 			tm.mu.RLock()
 			url := tm.currentURL
 			tm.mu.RUnlock()
@@ -186,7 +319,7 @@ func (tm *TabMonitor) handleEvent(ev interface{}) {
 		}
 
 	case *page.EventDomContentEventFired:
-		if cfg.EnablePage {
+		if cfg.EnablePage && tm.origin.FrameID == "" {
 			tm.mu.RLock()
 			url := tm.currentURL
 			tm.mu.RUnlock()
@@ -197,34 +330,48 @@ func (tm *TabMonitor) handleEvent(ev interface{}) {
 	// Network events
 	case *network.EventRequestWillBeSent:
 		if cfg.EnableNetwork {
-			tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkRequest, &events.NetworkRequestData{
-				RequestID: ev.RequestID.String(),
-				URL:       ev.Request.URL,
-				Method:    ev.Request.Method,
-				Type:      ev.Type.String(),
-			}))
+			tm.handleRequestWillBeSent(ev, site, tabID)
+		}
+
+	case *network.EventResponseReceivedExtraInfo:
+		if cfg.EnableNetwork && cfg.EnableStorage {
+			tm.handleSetCookies(ev, site, tabID)
 		}
 
 	case *network.EventResponseReceived:
 		if cfg.EnableNetwork {
-			headers := make(map[string]interface{})
-			for k, v := range ev.Response.Headers {
-				headers[k] = v
-			}
-
 			// Apply redaction to headers.
-			headers = tm.redactor.RedactHeaders(headers)
+			headers := tm.redactor.RedactHeaders(convertHeaders(ev.Response.Headers))
+
+			resp := ev.Response
+			tm.trackResponseTiming(ev.RequestID, resp.Timing)
 
 			tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkResponse, &events.NetworkResponseData{
-				RequestID:     ev.RequestID.String(),
-				URL:           ev.Response.URL,
-				Status:        ev.Response.Status,
-				StatusText:    ev.Response.StatusText,
-				MimeType:      ev.Response.MimeType,
-				Headers:       headers,
-				EncodedLength: ev.Response.EncodedDataLength,
+				RequestID:                   ev.RequestID.String(),
+				URL:                         resp.URL,
+				Status:                      resp.Status,
+				StatusText:                  resp.StatusText,
+				MimeType:                    resp.MimeType,
+				Headers:                     headers,
+				EncodedLength:               resp.EncodedDataLength,
+				Protocol:                    resp.Protocol,
+				RemoteIP:                    resp.RemoteIPAddress,
+				RemotePort:                  resp.RemotePort,
+				ConnectionReused:            resp.ConnectionReused,
+				ConnectionID:                resp.ConnectionID,
+				FromDiskCache:               resp.FromDiskCache,
+				FromPrefetchCache:           resp.FromPrefetchCache,
+				FromServiceWorker:           resp.FromServiceWorker,
+				ServiceWorkerResponseSource: resp.ServiceWorkerResponseSource.String(),
+				SecurityState:               resp.SecurityState.String(),
+				Timing:                      convertTiming(resp.Timing),
+				RedirectChain:               tm.redirectChain(ev.RequestID),
 			}))
 
+			if cfg.EnableSecurity {
+				tm.handleSecurityDetails(ev.RequestID, resp, site, tabID)
+			}
+
 			// Store response info for body capture if enabled
 			if cfg.CaptureBodies && tm.shouldCaptureBody(ev.Response.MimeType, ev.Response.EncodedDataLength) {
 				tm.trackerMu.Lock()
@@ -232,13 +379,18 @@ func (tm *TabMonitor) handleEvent(ev interface{}) {
 					URL:         ev.Response.URL,
 					MimeType:    ev.Response.MimeType,
 					ContentSize: ev.Response.EncodedDataLength,
-				CreatedAt:   time.Now(),
+					CreatedAt:   time.Now(),
 				}
 				tm.trackerMu.Unlock()
 			}
 		}
 
 	case *network.EventLoadingFinished:
+		if cfg.EnableNetwork {
+			tm.forgetEventSource(ev.RequestID)
+			tm.handleLoadingFinished(ev, site, tabID)
+		}
+
 		// Capture body after loading finished (if configured)
 		if cfg.EnableNetwork && cfg.CaptureBodies {
 			tm.trackerMu.Lock()
@@ -255,6 +407,9 @@ func (tm *TabMonitor) handleEvent(ev interface{}) {
 
 	case *network.EventLoadingFailed:
 		if cfg.EnableNetwork {
+			tm.forgetEventSource(ev.RequestID)
+			tm.forgetRequestTiming(ev.RequestID)
+
 			tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkFailure, &events.NetworkFailureData{
 				RequestID: ev.RequestID.String(),
 				ErrorText: ev.ErrorText,
@@ -264,52 +419,157 @@ func (tm *TabMonitor) handleEvent(ev interface{}) {
 			}))
 		}
 
+	case *network.EventEventSourceMessageReceived:
+		if cfg.EnableNetwork {
+			tm.handleEventSourceMessage(ev, site, tabID)
+		}
+
+	case *network.EventWebSocketCreated,
+		*network.EventWebSocketFrameSent,
+		*network.EventWebSocketFrameReceived,
+		*network.EventWebSocketClosed,
+		*network.EventWebSocketFrameError:
+		if cfg.EnableNetwork && cfg.EnableWebSocket {
+			tm.handleWebSocketEvent(ev, site, tabID)
+		}
+
 	// Console events
 	case *runtime.EventConsoleAPICalled:
 		if cfg.EnableConsole {
-			eventType := events.EventConsoleLog
-			switch ev.Type {
-			case runtime.APITypeWarning:
-				eventType = events.EventConsoleWarn
-			case runtime.APITypeError:
-				eventType = events.EventConsoleError
-			case runtime.APITypeInfo:
-				eventType = events.EventConsoleInfo
-			case runtime.APITypeDebug:
-				eventType = events.EventConsoleDebug
-			}
+			tm.handleConsoleAPICalled(ev, site, tabID)
+		}
 
-			args := make([]interface{}, 0, len(ev.Args))
-			for _, arg := range ev.Args {
-				args = append(args, extractRemoteObjectValue(arg))
-			}
+	// Worker and out-of-process iframe targets
+	case *target.EventAttachedToTarget:
+		tm.handleAttachedToTarget(ev.SessionID, ev.TargetInfo)
 
-			tm.writeEvent(events.NewLogEvent(site, tabID, eventType, &events.ConsoleData{
-				Args: args,
-			}))
+	case *target.EventDetachedFromTarget:
+		tm.detachChild(ev.SessionID)
+
+	// Browser log events
+	case *cdplog.EventEntryAdded:
+		if cfg.EnableBrowserLog {
+			tm.handleLogEntry(ev.Entry, site, tabID)
 		}
 
+	// Audit events
+	case *audits.EventIssueAdded:
+		if cfg.EnableAudits {
+			tm.handleIssueAdded(ev.Issue, site, tabID)
+		}
+
+	// Security events
+	case *security.EventVisibleSecurityStateChanged:
+		if cfg.EnableSecurity {
+			tm.handleSecurityStateChanged(ev.VisibleSecurityState, site, tabID)
+		}
+
+	// Storage events
+	case *domstorage.EventDomStorageItemAdded,
+		*domstorage.EventDomStorageItemUpdated,
+		*domstorage.EventDomStorageItemRemoved,
+		*domstorage.EventDomStorageItemsCleared:
+		if cfg.EnableStorage {
+			tm.handleDOMStorageEvent(ev, site, tabID)
+		}
+
+	case *storage.EventIndexedDBContentUpdated,
+		*storage.EventIndexedDBListUpdated,
+		*storage.EventCacheStorageContentUpdated,
+		*storage.EventCacheStorageListUpdated:
+		if cfg.EnableStorage {
+			tm.handleStorageDomainEvent(ev, site, tabID)
+		}
+
+	// Performance events
+	case *runtime.EventBindingCalled:
+		tm.handleBindingCalled(ev, site, tabID)
+
+	// Script tracking for source maps
+	case *debugger.EventScriptParsed:
+		tm.trackScript(ev)
+
+	case *runtime.EventExecutionContextsCleared:
+		tm.clearScripts()
+		tm.resetConsoleGroups()
+
 	// Error events
 	case *runtime.EventExceptionThrown:
 		if cfg.EnableErrors {
 			details := ev.ExceptionDetails
-			tm.writeEvent(events.NewLogEvent(site, tabID, events.EventErrorRuntime, &events.RuntimeErrorData{
-				Text:     details.Text,
-				Line:     details.LineNumber,
-				Column:   details.ColumnNumber,
-				URL:      details.URL,
-				ScriptID: string(details.ScriptID),
+			if isUnhandledRejection(details) {
+				tm.handleUnhandledRejection(details, site, tabID)
+				break
+			}
+
+			class, message := exceptionClassAndMessage(details.Exception)
+			tm.writeSymbolicated(events.NewLogEvent(site, tabID, events.EventErrorRuntime, &events.RuntimeErrorData{
+				Text:             details.Text,
+				Line:             details.LineNumber,
+				Column:           details.ColumnNumber,
+				URL:              details.URL,
+				ScriptID:         string(details.ScriptID),
+				ExceptionClass:   class,
+				ExceptionMessage: message,
+				Stack:            convertStackTrace(details.StackTrace),
 			}))
 		}
+
+	case *runtime.EventExceptionRevoked:
+		if cfg.EnableErrors {
+			tm.handleExceptionRevoked(ev, site, tabID)
+		}
 	}
 }
 
-// writeEvent writes an event to the log file.
-func (tm *TabMonitor) writeEvent(ev *events.LogEvent) {
-	if err := tm.fileManager.WriteEvent(tm.tabID, ev); err != nil {
-		log.Printf("Warning: failed to write event (tab %s, type %s): %v",
-			tm.tabID, ev.EventType, err)
+// handleRequestWillBeSent logs an outgoing request, including its headers and,
+// when body capture is enabled, its post data.
+func (tm *TabMonitor) handleRequestWillBeSent(ev *network.EventRequestWillBeSent, site, tabID string) {
+	req := ev.Request
+
+	data := &events.NetworkRequestData{
+		RequestID: ev.RequestID.String(),
+		URL:       req.URL,
+		Method:    req.Method,
+		Type:      ev.Type.String(),
+		Headers:   tm.redactor.RedactHeaders(convertHeaders(req.Headers)),
+		Initiator: convertInitiator(ev.Initiator),
+		Priority:  req.InitialPriority.String(),
+	}
+
+	tm.trackRequestStart(ev.RequestID, req.URL, ev.Timestamp)
+	tm.trackNavigationReferrer(ev)
+
+	// A request re-sent with a redirect response follows a 3xx
+	if ev.RedirectResponse != nil {
+		tm.handleRedirect(ev, site, tabID)
 	}
+
+	if ev.Type == network.ResourceTypeEventSource {
+		tm.trackEventSource(ev.RequestID, req.URL)
+	}
+
+	if tm.config.CaptureBodies && req.HasPostData {
+		mimeType := headerValue(req.Headers, "Content-Type")
+		if postData, ok := decodePostDataEntries(req.PostDataEntries); ok {
+			if tm.shouldCaptureBody(mimeType, float64(len(postData))) {
+				data.PostData = tm.redactPostData(postData, mimeType)
+			}
+		} else if tm.shouldCaptureBody(mimeType, 0) {
+			// Post data was too large to be inlined; fetch it separately.
+			go tm.capturePostData(ev.RequestID, req.URL, mimeType, site, tabID)
+		}
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkRequest, data))
+}
+
+// writeEvent writes an event to the sink.
+// Events from child monitors are tagged with the worker or frame they came
+// from; the innermost origin wins for nested targets.
+func (tm *TabMonitor) writeEvent(ev *events.LogEvent) {
+	top := tm.topLevel(ev)
+	top.output(func() { top.writeToSink(ev) })
 }
 
 // shouldCaptureBody checks if response body should be captured based on content type and size.
@@ -344,6 +604,20 @@ func (tm *TabMonitor) cleanExpiredRequests(maxAge time.Duration) {
 	}
 }
 
+// cleanOrphanedTimings removes request timings older than maxAge. Timings
+// are normally forgotten on loadingFinished or loadingFailed, so this only
+// catches requests Chrome never reported the end of.
+func (tm *TabMonitor) cleanOrphanedTimings(maxAge time.Duration) {
+	cutoff := time.Now().Add(-maxAge)
+	tm.trackerMu.Lock()
+	defer tm.trackerMu.Unlock()
+	for id, rt := range tm.requestTimings {
+		if rt.CreatedAt.Before(cutoff) {
+			delete(tm.requestTimings, id)
+		}
+	}
+}
+
 // matchContentType checks if a mime type matches a pattern (supports wildcards like "text/*").
 func matchContentType(actual, pattern string) bool {
 	// Remove parameters (e.g., "text/html; charset=utf-8" → "text/html")
@@ -360,6 +634,20 @@ func matchContentType(actual, pattern string) bool {
 	return actual == pattern
 }
 
+// truncatePayload shortens s to at most limit bytes without splitting a
+// UTF-8 sequence. Returns true if s was truncated.
+func truncatePayload(s string, limit int) (string, bool) {
+	if limit <= 0 || len(s) <= limit {
+		return s, false
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut], true
+}
+
 // captureBody retrieves and logs the response body.
 func (tm *TabMonitor) captureBody(requestID network.RequestID, info *responseInfo, site, tabID string) {
 	tm.mu.RLock()
@@ -404,6 +692,101 @@ func (tm *TabMonitor) captureBody(requestID network.RequestID, info *responseInf
 	}))
 }
 
+// capturePostData retrieves and logs request post data that was omitted
+// from the original network.request event.
+func (tm *TabMonitor) capturePostData(requestID network.RequestID, url, mimeType, site, tabID string) {
+	tm.mu.RLock()
+	tCtx := tm.targetCtx
+	tm.mu.RUnlock()
+
+	if tCtx == nil {
+		return
+	}
+
+	var postData string
+	err := chromedp.Run(tCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		postData, err = network.GetRequestPostData(requestID).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		// Post data is no longer available (request may have been discarded)
+		return
+	}
+
+	if !tm.shouldCaptureBody(mimeType, float64(len(postData))) {
+		return
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkRequestBody, &events.NetworkRequestBodyData{
+		RequestID: requestID.String(),
+		URL:       url,
+		MimeType:  mimeType,
+		Body:      tm.redactPostData(postData, mimeType),
+	}))
+}
+
+// redactPostData applies the redaction strategy matching the body's content type.
+func (tm *TabMonitor) redactPostData(body, mimeType string) string {
+	if matchContentType(strings.ToLower(mimeType), "application/x-www-form-urlencoded") {
+		return tm.redactor.RedactFormBody(body)
+	}
+	return tm.redactor.RedactBody(body)
+}
+
+// convertHeaders converts CDP headers into a generic map for logging.
+func convertHeaders(h network.Headers) map[string]interface{} {
+	headers := make(map[string]interface{}, len(h))
+	for k, v := range h {
+		headers[k] = v
+	}
+	return headers
+}
+
+// headerValue returns the value of a header by case-insensitive name.
+func headerValue(h network.Headers, name string) string {
+	for k, v := range h {
+		if strings.EqualFold(k, name) {
+			if s, ok := v.(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// decodePostDataEntries joins the base64-encoded post data entries of a request.
+// Returns false if there are no entries or any entry fails to decode.
+func decodePostDataEntries(entries []*network.PostDataEntry) (string, bool) {
+	if len(entries) == 0 {
+		return "", false
+	}
+
+	var sb strings.Builder
+	for _, entry := range entries {
+		b, err := base64.StdEncoding.DecodeString(entry.Bytes)
+		if err != nil {
+			return "", false
+		}
+		sb.Write(b)
+	}
+	return sb.String(), true
+}
+
+// convertInitiator extracts the loggable fields of a request initiator.
+func convertInitiator(init *network.Initiator) *events.NetworkInitiatorData {
+	if init == nil {
+		return nil
+	}
+	return &events.NetworkInitiatorData{
+		Type:      init.Type.String(),
+		URL:       init.URL,
+		Line:      init.LineNumber,
+		Column:    init.ColumnNumber,
+		RequestID: init.RequestID.String(),
+	}
+}
+
 // HandleSiteChange handles navigation to a different site.
 // Returns true if the site actually changed.
 func (tm *TabMonitor) HandleSiteChange(newSite, newURL string) bool {
@@ -416,41 +799,57 @@ func (tm *TabMonitor) HandleSiteChange(newSite, newURL string) bool {
 	}
 
 	oldSite := tm.currentSite
-
-	// Write meta event to old log
-	if err := tm.fileManager.WriteEvent(tm.tabID, events.NewSiteChangedEvent(
-		oldSite,
-		tm.tabID,
-		newSite,
-		newURL,
-	)); err != nil {
-		log.Printf("Warning: failed to write site_changed event (tab %s): %v", tm.tabID, err)
-	}
-
-	// Close old log file
-	if err := tm.fileManager.CloseTab(tm.tabID, oldSite); err != nil {
-		log.Printf("Warning: failed to close old site log (tab %s, site %s): %v", tm.tabID, oldSite, err)
-	}
+	tabID := tm.tabID
 
 	// Update current site
 	tm.currentSite = newSite
 	tm.currentURL = newURL
 
-	// Write meta event to new log
-	if err := tm.fileManager.WriteEvent(tm.tabID, events.NewSiteEnteredEvent(
-		newSite,
-		tm.tabID,
-		oldSite,
-		newURL,
-	)); err != nil {
-		log.Printf("Warning: failed to write site_entered event (tab %s): %v", tm.tabID, err)
-	}
+	// Switch logs after events still queued for the old site
+	tm.output(func() {
+		// Write meta event to old log
+		if err := tm.sink.WriteEvent(tabID, events.NewSiteChangedEvent(
+			oldSite,
+			tabID,
+			newSite,
+			newURL,
+		)); err != nil {
+			log.Printf("Warning: failed to write site_changed event (tab %s): %v", tabID, err)
+		}
+
+		// Close old log file
+		if err := tm.sink.CloseTab(tabID, oldSite); err != nil {
+			log.Printf("Warning: failed to close old site log (tab %s, site %s): %v", tabID, oldSite, err)
+		}
+
+		// Write meta event to new log
+		if err := tm.sink.WriteEvent(tabID, events.NewSiteEnteredEvent(
+			newSite,
+			tabID,
+			oldSite,
+			newURL,
+		)); err != nil {
+			log.Printf("Warning: failed to write site_entered event (tab %s): %v", tabID, err)
+		}
+	})
 
 	return true
 }
 
-// Stop gracefully stops the tab monitor.
+// Stop gracefully stops the tab monitor. It is safe to call more than once.
 func (tm *TabMonitor) Stop() {
+	tm.stopOnce.Do(tm.stop)
+}
+
+// stop writes the closing events and releases the monitor's log file.
+func (tm *TabMonitor) stop() {
+	tm.stopChildren()
+
+	if tm.origin.isChild() {
+		tm.stopChild()
+		return
+	}
+
 	tm.mu.RLock()
 	site := tm.currentSite
 	tabID := tm.tabID
@@ -469,10 +868,12 @@ func (tm *TabMonitor) Stop() {
 		duration,
 	))
 
-	// Close log file (errors are non-fatal during shutdown)
-	if err := tm.fileManager.CloseTab(tabID, site); err != nil {
-		_ = err
-	}
+	// Close log file once queued events are written
+	// (errors are non-fatal during shutdown)
+	tm.output(func() {
+		_ = tm.sink.CloseTab(tabID, site)
+	})
+	tm.closeOutput()
 
 	// Cancel context
 	tm.cancel()
@@ -1,14 +1,65 @@
 package monitor
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/chromedp/cdproto/network"
 
 	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/logger"
+	"github.com/ajsharma/browser_tail/internal/redact"
 )
 
+// newTestMonitor creates a TabMonitor that writes to a temporary directory.
+func newTestMonitor(t *testing.T, cfg *config.Config) (*TabMonitor, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	fm := logger.NewFileManager(dir)
+	t.Cleanup(func() { _ = fm.Close() })
+
+	tm := NewTabMonitor(context.Background(), "target-1", "tab-1", "example.com", "", "https://example.com", "", fm, cfg)
+	t.Cleanup(tm.cancel)
+	return tm, dir
+}
+
+// openTestFiles returns the number of log files the monitor's sink has open.
+func openTestFiles(tm *TabMonitor) int {
+	return tm.sink.(*logger.FileManager).GetOpenFiles()
+}
+
+// readTestEvents flushes the monitor's log and decodes every event in it.
+func readTestEvents(t *testing.T, tm *TabMonitor, dir string) []map[string]interface{} {
+	t.Helper()
+
+	if err := tm.sink.CloseTab(tm.tabID, tm.currentSite); err != nil {
+		t.Fatalf("CloseTab failed: %v", err)
+	}
+
+	f, err := os.Open(logger.GetLogPath(dir, tm.currentSite, tm.tabID))
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	defer f.Close()
+
+	var result []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("failed to decode event: %v", err)
+		}
+		result = append(result, ev)
+	}
+	return result
+}
+
 func TestCleanExpiredRequests(t *testing.T) {
 	cfg := config.DefaultConfig()
 	tm := &TabMonitor{
@@ -47,6 +98,32 @@ func TestCleanExpiredRequests(t *testing.T) {
 	}
 }
 
+func TestCleanOrphanedTimings(t *testing.T) {
+	tm := &TabMonitor{
+		config:         config.DefaultConfig(),
+		requestTracker: make(map[network.RequestID]*responseInfo),
+		requestTimings: make(map[network.RequestID]*requestTiming),
+	}
+
+	now := time.Now()
+	tm.requestTimings["long-download"] = &requestTiming{URL: "https://example.com/big.zip", CreatedAt: now.Add(-10 * time.Minute)}
+	tm.requestTimings["orphan"] = &requestTiming{URL: "https://example.com/lost", CreatedAt: now.Add(-2 * orphanedTimingAge)}
+
+	// Body tracking expiry leaves requests that are still loading alone
+	tm.cleanExpiredRequests(60 * time.Second)
+	if len(tm.requestTimings) != 2 {
+		t.Fatalf("expected timings to outlive body tracker expiry, got %d", len(tm.requestTimings))
+	}
+
+	tm.cleanOrphanedTimings(orphanedTimingAge)
+	if _, exists := tm.requestTimings["long-download"]; !exists {
+		t.Error("expected the long download to keep its timing")
+	}
+	if _, exists := tm.requestTimings["orphan"]; exists {
+		t.Error("expected the orphaned timing to be removed")
+	}
+}
+
 func TestCleanExpiredRequestsEmpty(t *testing.T) {
 	cfg := config.DefaultConfig()
 	tm := &TabMonitor{
@@ -61,3 +138,86 @@ func TestCleanExpiredRequestsEmpty(t *testing.T) {
 		t.Fatalf("expected 0 entries, got %d", len(tm.requestTracker))
 	}
 }
+
+func TestDecodePostDataEntries(t *testing.T) {
+	entries := []*network.PostDataEntry{
+		{Bytes: "dXNlcj1qb2hu"}, // user=john
+		{Bytes: "JnBhc3M9eA=="}, // &pass=x
+	}
+
+	got, ok := decodePostDataEntries(entries)
+	if !ok {
+		t.Fatal("expected entries to decode")
+	}
+	if got != "user=john&pass=x" {
+		t.Errorf("decodePostDataEntries() = %q, want %q", got, "user=john&pass=x")
+	}
+
+	if _, ok := decodePostDataEntries(nil); ok {
+		t.Error("expected no entries to report not ok")
+	}
+	if _, ok := decodePostDataEntries([]*network.PostDataEntry{{Bytes: "!!"}}); ok {
+		t.Error("expected invalid base64 to report not ok")
+	}
+}
+
+func TestHeaderValue(t *testing.T) {
+	headers := network.Headers{
+		"content-type": "application/json",
+		"X-Count":      float64(3),
+	}
+
+	if got := headerValue(headers, "Content-Type"); got != "application/json" {
+		t.Errorf("headerValue(Content-Type) = %q, want %q", got, "application/json")
+	}
+	if got := headerValue(headers, "X-Count"); got != "" {
+		t.Errorf("headerValue(X-Count) = %q, want empty for non-string", got)
+	}
+	if got := headerValue(headers, "Accept"); got != "" {
+		t.Errorf("headerValue(Accept) = %q, want empty", got)
+	}
+}
+
+func TestRedactPostData(t *testing.T) {
+	cfg := config.DefaultConfig()
+	tm := &TabMonitor{
+		config:   cfg,
+		redactor: redact.New(true),
+	}
+
+	form := tm.redactPostData("user=john&password=hunter2", "application/x-www-form-urlencoded; charset=UTF-8")
+	if strings.Contains(form, "hunter2") {
+		t.Errorf("expected form password to be redacted, got %q", form)
+	}
+
+	body := tm.redactPostData(`{"password":"hunter2"}`, "application/json")
+	if strings.Contains(body, "hunter2") {
+		t.Errorf("expected JSON password to be redacted, got %q", body)
+	}
+}
+
+func TestTruncatePayload(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		limit         int
+		want          string
+		wantTruncated bool
+	}{
+		{"under limit", "hello", 10, "hello", false},
+		{"at limit", "hello", 5, "hello", false},
+		{"over limit", "hello world", 5, "hello", true},
+		{"no limit", "hello", 0, "hello", false},
+		{"utf-8 boundary", "héllo", 2, "h", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := truncatePayload(tt.input, tt.limit)
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("truncatePayload(%q, %d) = (%q, %v), want (%q, %v)",
+					tt.input, tt.limit, got, truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package monitor
+
+import (
+	"math"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// orphanedTimingAge is how long a request is tracked without Chrome
+// reporting that it finished or failed. It is only a backstop: long
+// downloads, long polls and slow uploads must keep their timing until they
+// end.
+const orphanedTimingAge = 6 * time.Hour
+
+// requestTiming tracks a request between being sent and finishing loading.
+// Start and HeadersEnd are CDP monotonic times in seconds. Redirects lists
+// the responses that redirected the request, in order.
+type requestTiming struct {
+	URL        string
+	Start      float64
+	HeadersEnd float64
+	Redirects  []events.RedirectHop
+	CreatedAt  time.Time
+}
+
+// trackRequestStart records when a request was sent. Redirects reuse the
+// request ID, so the first hop's start time is kept.
+func (tm *TabMonitor) trackRequestStart(requestID network.RequestID, url string, ts *cdp.MonotonicTime) {
+	tm.trackerMu.Lock()
+	defer tm.trackerMu.Unlock()
+
+	if _, exists := tm.requestTimings[requestID]; exists {
+		return
+	}
+	tm.requestTimings[requestID] = &requestTiming{
+		URL:       url,
+		Start:     monotonicSeconds(ts),
+		CreatedAt: time.Now(),
+	}
+}
+
+// trackResponseTiming records when a request's response headers arrived.
+func (tm *TabMonitor) trackResponseTiming(requestID network.RequestID, timing *network.ResourceTiming) {
+	if timing == nil {
+		return
+	}
+
+	tm.trackerMu.Lock()
+	defer tm.trackerMu.Unlock()
+
+	if rt, exists := tm.requestTimings[requestID]; exists {
+		rt.HeadersEnd = timing.RequestTime + timing.ReceiveHeadersEnd/1000
+	}
+}
+
+// forgetRequestTiming stops tracking a request and returns what was tracked.
+func (tm *TabMonitor) forgetRequestTiming(requestID network.RequestID) *requestTiming {
+	tm.trackerMu.Lock()
+	defer tm.trackerMu.Unlock()
+
+	rt := tm.requestTimings[requestID]
+	delete(tm.requestTimings, requestID)
+	return rt
+}
+
+// handleLoadingFinished logs the final byte count and timing of a request.
+func (tm *TabMonitor) handleLoadingFinished(ev *network.EventLoadingFinished, site, tabID string) {
+	data := &events.NetworkFinishedData{
+		RequestID:         ev.RequestID.String(),
+		EncodedDataLength: ev.EncodedDataLength,
+	}
+
+	if rt := tm.forgetRequestTiming(ev.RequestID); rt != nil {
+		finished := monotonicSeconds(ev.Timestamp)
+		data.URL = rt.URL
+		if rt.Start > 0 && finished >= rt.Start {
+			data.DurationMs = roundMs((finished - rt.Start) * 1000)
+		}
+		if rt.HeadersEnd > 0 && finished >= rt.HeadersEnd {
+			data.ReceiveMs = roundMs((finished - rt.HeadersEnd) * 1000)
+		}
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkFinished, data))
+}
+
+// convertTiming converts CDP resource timing into per-phase durations.
+// Phases that did not happen (e.g. DNS on a reused connection) are zero.
+func convertTiming(t *network.ResourceTiming) *events.NetworkTimingData {
+	if t == nil {
+		return nil
+	}
+
+	// Waiting ends when the first header byte arrives, if Chrome reports it
+	headersStart := t.ReceiveHeadersStart
+	if headersStart <= 0 {
+		headersStart = t.ReceiveHeadersEnd
+	}
+
+	return &events.NetworkTimingData{
+		ProxyMs:   phaseMs(t.ProxyStart, t.ProxyEnd),
+		DNSMs:     phaseMs(t.DNSStart, t.DNSEnd),
+		ConnectMs: phaseMs(t.ConnectStart, t.ConnectEnd),
+		TLSMs:     phaseMs(t.SslStart, t.SslEnd),
+		WorkerMs:  phaseMs(t.WorkerStart, t.WorkerRespondWithSettled),
+		SendMs:    phaseMs(t.SendStart, t.SendEnd),
+		WaitMs:    phaseMs(t.SendEnd, headersStart),
+		HeadersMs: roundMs(math.Max(t.ReceiveHeadersEnd, 0)),
+	}
+}
+
+// phaseMs returns the duration between two timing ticks. CDP reports -1
+// for phases that did not occur.
+func phaseMs(start, end float64) float64 {
+	if start < 0 || end < start {
+		return 0
+	}
+	return roundMs(end - start)
+}
+
+// roundMs rounds a millisecond duration to microsecond precision.
+func roundMs(ms float64) float64 {
+	return math.Round(ms*1000) / 1000
+}
+
+// monotonicSeconds converts a CDP monotonic timestamp back to seconds.
+func monotonicSeconds(ts *cdp.MonotonicTime) float64 {
+	if ts == nil {
+		return 0
+	}
+	return ts.Time().Sub(*cdp.MonotonicTimeEpoch).Seconds()
+}
@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// monotonicAt returns a CDP monotonic timestamp the given seconds after the epoch.
+func monotonicAt(seconds float64) *cdp.MonotonicTime {
+	ts := cdp.MonotonicTime(cdp.MonotonicTimeEpoch.Add(time.Duration(seconds * float64(time.Second))))
+	return &ts
+}
+
+func TestConvertTiming(t *testing.T) {
+	timing := convertTiming(&network.ResourceTiming{
+		RequestTime:         100,
+		ProxyStart:          -1,
+		ProxyEnd:            -1,
+		DNSStart:            1,
+		DNSEnd:              11,
+		ConnectStart:        11,
+		ConnectEnd:          41,
+		SslStart:            21,
+		SslEnd:              41,
+		WorkerStart:         -1,
+		SendStart:           41.5,
+		SendEnd:             42,
+		ReceiveHeadersStart: 142,
+		ReceiveHeadersEnd:   143,
+	})
+
+	want := events.NetworkTimingData{
+		DNSMs:     10,
+		ConnectMs: 30,
+		TLSMs:     20,
+		SendMs:    0.5,
+		WaitMs:    100,
+		HeadersMs: 143,
+	}
+	if *timing != want {
+		t.Errorf("convertTiming() = %+v, want %+v", *timing, want)
+	}
+
+	if convertTiming(nil) != nil {
+		t.Error("expected nil timing for nil input")
+	}
+}
+
+func TestLoadingFinishedDuration(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+	id := network.RequestID("req-1")
+
+	tm.handleEvent(&network.EventRequestWillBeSent{
+		RequestID: id,
+		Request:   &network.Request{URL: "https://example.com/app.js", Method: "GET"},
+		Timestamp: monotonicAt(100),
+	})
+	tm.handleEvent(&network.EventResponseReceived{
+		RequestID: id,
+		Response: &network.Response{
+			URL:             "https://example.com/app.js",
+			Status:          200,
+			Protocol:        "h2",
+			RemoteIPAddress: "93.184.216.34",
+			RemotePort:      443,
+			FromDiskCache:   true,
+			Timing:          &network.ResourceTiming{RequestTime: 100, ReceiveHeadersEnd: 200, DNSStart: -1, DNSEnd: -1},
+		},
+	})
+	tm.handleEvent(&network.EventLoadingFinished{
+		RequestID:         id,
+		Timestamp:         monotonicAt(100.5),
+		EncodedDataLength: 2048,
+	})
+
+	if len(tm.requestTimings) != 0 {
+		t.Errorf("expected request timing to be forgotten, got %d tracked", len(tm.requestTimings))
+	}
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(got))
+	}
+
+	resp := got[1]["data"].(map[string]interface{})
+	if resp["protocol"] != "h2" || resp["remote_ip"] != "93.184.216.34" || resp["from_disk_cache"] != true {
+		t.Errorf("unexpected connection details: %v", resp)
+	}
+	timing := resp["timing"].(map[string]interface{})
+	if _, ok := timing["dns_ms"]; ok {
+		t.Errorf("expected skipped DNS phase to be omitted, got %v", timing["dns_ms"])
+	}
+
+	if got[2]["event_type"] != events.EventNetworkFinished {
+		t.Fatalf("expected %s, got %v", events.EventNetworkFinished, got[2]["event_type"])
+	}
+	finished := got[2]["data"].(map[string]interface{})
+	if finished["encoded_data_length"] != float64(2048) {
+		t.Errorf("expected 2048 bytes, got %v", finished["encoded_data_length"])
+	}
+	if finished["duration_ms"] != float64(500) {
+		t.Errorf("expected duration 500ms, got %v", finished["duration_ms"])
+	}
+	if finished["receive_ms"] != float64(300) {
+		t.Errorf("expected receive 300ms, got %v", finished["receive_ms"])
+	}
+	if finished["url"] != "https://example.com/app.js" {
+		t.Errorf("expected url to be carried over, got %v", finished["url"])
+	}
+}
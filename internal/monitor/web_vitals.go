@@ -0,0 +1,41 @@
+package monitor
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+const (
+	// webVitalsBinding is the Runtime binding the injected script reports through.
+	webVitalsBinding = "__browserTailVitals"
+
+	// maxShiftSources matches the number of CLS sources the script reports.
+	maxShiftSources = 5
+)
+
+// webVitalsScript observes paint, layout shift and interaction timings and
+// reports them through webVitalsBinding after load and on page hide.
+//
+//go:embed web_vitals.js
+var webVitalsScript string
+
+// enableWebVitals installs the Web Vitals collector.
+func enableWebVitals(targetCtx context.Context) error {
+	return injectScript(targetCtx, webVitalsBinding, webVitalsScript)
+}
+
+// handleWebVitals logs a Web Vitals report from the injected script.
+func (tm *TabMonitor) handleWebVitals(payload, site, tabID string) {
+	var data events.WebVitalsData
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		return
+	}
+	if len(data.CLSSources) > maxShiftSources {
+		data.CLSSources = data.CLSSources[:maxShiftSources]
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, events.EventPerfWebVitals, &data))
+}
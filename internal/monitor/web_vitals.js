@@ -0,0 +1,182 @@
+// Collects Core Web Vitals for the top-level document and reports them to
+// browser_tail through the __browserTailVitals binding: once after load, and
+// again whenever the page is hidden.
+(function () {
+  'use strict';
+
+  var send = window.__browserTailVitals;
+  if (typeof send !== 'function' || window.top !== window || window.__browserTailVitalsInstalled) {
+    return;
+  }
+  Object.defineProperty(window, '__browserTailVitalsInstalled', { value: true });
+
+  var MAX_SHIFT_SOURCES = 5;
+
+  var lcp = null;
+  var fcp = 0;
+  var cls = 0;
+  var session = { value: 0, start: 0, last: 0, shifts: [] };
+  var worstSession = { value: 0, shifts: [] };
+  var interactions = {};
+
+  function selector(node) {
+    if (!node || node.nodeType !== 1) {
+      return '';
+    }
+    var parts = [];
+    for (var el = node; el && el.nodeType === 1 && parts.length < 5; el = el.parentElement) {
+      if (el.id) {
+        parts.unshift('#' + el.id);
+        break;
+      }
+      var part = el.localName;
+      if (el.classList && el.classList.length) {
+        part += '.' + Array.prototype.slice.call(el.classList, 0, 2).join('.');
+      }
+      var parent = el.parentElement;
+      if (parent) {
+        var siblings = Array.prototype.filter.call(parent.children, function (c) {
+          return c.localName === el.localName;
+        });
+        if (siblings.length > 1) {
+          part += ':nth-of-type(' + (siblings.indexOf(el) + 1) + ')';
+        }
+      }
+      parts.unshift(part);
+    }
+    return parts.join(' > ');
+  }
+
+  function observe(type, callback, options) {
+    try {
+      var po = new PerformanceObserver(function (list) {
+        list.getEntries().forEach(callback);
+      });
+      var opts = { type: type, buffered: true };
+      for (var key in options) {
+        opts[key] = options[key];
+      }
+      po.observe(opts);
+    } catch (e) {
+      // Entry type not supported by this browser
+    }
+  }
+
+  observe('paint', function (entry) {
+    if (entry.name === 'first-contentful-paint') {
+      fcp = entry.startTime;
+    }
+  });
+
+  observe('largest-contentful-paint', function (entry) {
+    lcp = {
+      value: entry.startTime,
+      element: selector(entry.element),
+      url: entry.url || ''
+    };
+  });
+
+  // CLS is the largest burst of shifts less than 1s apart, capped at 5s
+  observe('layout-shift', function (entry) {
+    if (entry.hadRecentInput) {
+      return;
+    }
+    if (session.shifts.length && (entry.startTime - session.last > 1000 || entry.startTime - session.start > 5000)) {
+      session = { value: 0, start: entry.startTime, last: 0, shifts: [] };
+    }
+    if (!session.shifts.length) {
+      session.start = entry.startTime;
+    }
+    session.value += entry.value;
+    session.last = entry.startTime;
+    session.shifts.push(entry);
+    if (session.value > worstSession.value) {
+      worstSession = { value: session.value, shifts: session.shifts.slice() };
+    }
+    cls = worstSession.value;
+  });
+
+  observe('event', function (entry) {
+    if (!entry.interactionId) {
+      return;
+    }
+    var current = interactions[entry.interactionId];
+    if (!current || entry.duration > current.duration) {
+      interactions[entry.interactionId] = {
+        duration: entry.duration,
+        event: entry.name,
+        target: selector(entry.target)
+      };
+    }
+  }, { durationThreshold: 40 });
+
+  function shiftSources() {
+    var totals = {};
+    worstSession.shifts.forEach(function (entry) {
+      var sources = entry.sources || [];
+      sources.forEach(function (source) {
+        var sel = selector(source.node);
+        if (sel) {
+          totals[sel] = (totals[sel] || 0) + entry.value / sources.length;
+        }
+      });
+    });
+    return Object.keys(totals)
+      .map(function (sel) { return { selector: sel, value: totals[sel] }; })
+      .sort(function (a, b) { return b.value - a.value; })
+      .slice(0, MAX_SHIFT_SOURCES);
+  }
+
+  // INP approximates the 98th percentile: the worst interaction, ignoring
+  // one outlier per 50 interactions
+  function inp() {
+    var list = Object.keys(interactions)
+      .map(function (id) { return interactions[id]; })
+      .sort(function (a, b) { return b.duration - a.duration; });
+    if (!list.length) {
+      return null;
+    }
+    return list[Math.min(list.length - 1, Math.floor(list.length / 50))];
+  }
+
+  function report(reason) {
+    var nav = performance.getEntriesByType('navigation')[0];
+    var worst = inp();
+    var data = {
+      reason: reason,
+      url: location.href,
+      lcp_ms: lcp ? lcp.value : 0,
+      lcp_element: lcp ? lcp.element : '',
+      lcp_url: lcp ? lcp.url : '',
+      cls: cls,
+      cls_sources: shiftSources(),
+      inp_ms: worst ? worst.duration : 0,
+      inp_event: worst ? worst.event : '',
+      inp_target: worst ? worst.target : '',
+      fcp_ms: fcp,
+      ttfb_ms: nav ? nav.responseStart : 0
+    };
+    try {
+      send(JSON.stringify(data));
+    } catch (e) {
+      // Binding removed (monitor detached)
+    }
+  }
+
+  function reportAfterLoad() {
+    // Let load handlers and the paints they cause settle first
+    setTimeout(function () { report('load'); }, 0);
+  }
+
+  if (document.readyState === 'complete') {
+    reportAfterLoad();
+  } else {
+    window.addEventListener('load', reportAfterLoad, { once: true });
+  }
+
+  document.addEventListener('visibilitychange', function () {
+    if (document.visibilityState === 'hidden') {
+      report('hidden');
+    }
+  }, true);
+})();
@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chromedp/cdproto/runtime"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestWebVitalsBinding(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableWebVitals = true
+	tm, dir := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&runtime.EventBindingCalled{
+		Name:    webVitalsBinding,
+		Payload: `{"reason":"load","url":"https://example.com/","lcp_ms":1200.5,"lcp_element":"main > img","cls":0.05,"cls_sources":[{"selector":"#ad","value":0.05}],"fcp_ms":800,"ttfb_ms":120,"extra":"ignored"}`,
+	})
+	tm.handleEvent(&runtime.EventBindingCalled{Name: webVitalsBinding, Payload: `not json`})
+	tm.handleEvent(&runtime.EventBindingCalled{Name: "someOtherBinding", Payload: `{}`})
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(got))
+	}
+	if got[0]["event_type"] != events.EventPerfWebVitals {
+		t.Fatalf("expected %s, got %v", events.EventPerfWebVitals, got[0]["event_type"])
+	}
+
+	data := got[0]["data"].(map[string]interface{})
+	if data["lcp_ms"] != 1200.5 || data["lcp_element"] != "main > img" {
+		t.Errorf("unexpected LCP fields: %v", data)
+	}
+	if _, ok := data["extra"]; ok {
+		t.Error("expected unknown payload fields to be dropped")
+	}
+	sources, ok := data["cls_sources"].([]interface{})
+	if !ok || len(sources) != 1 {
+		t.Errorf("expected 1 CLS source, got %v", data["cls_sources"])
+	}
+}
+
+func TestWebVitalsDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableWebVitals = false
+	tm, _ := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&runtime.EventBindingCalled{Name: webVitalsBinding, Payload: `{"reason":"load"}`})
+
+	if openTestFiles(tm) != 0 {
+		t.Error("expected no events to be written when Web Vitals are disabled")
+	}
+}
+
+func TestWebVitalsScriptUsesBinding(t *testing.T) {
+	if !strings.Contains(webVitalsScript, webVitalsBinding) {
+		t.Errorf("expected embedded script to report through %s", webVitalsBinding)
+	}
+}
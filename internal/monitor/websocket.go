@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"github.com/chromedp/cdproto/network"
+
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+// wsOpcodeText is the WebSocket opcode for UTF-8 text frames.
+// Payloads of all other opcodes are base64-encoded by CDP.
+const wsOpcodeText = 1
+
+// webSocketInfo tracks an open WebSocket for frame correlation.
+type webSocketInfo struct {
+	URL            string
+	FramesSent     int64
+	FramesReceived int64
+}
+
+// handleWebSocketEvent processes WebSocket lifecycle and frame events.
+func (tm *TabMonitor) handleWebSocketEvent(ev interface{}, site, tabID string) {
+	switch ev := ev.(type) {
+	case *network.EventWebSocketCreated:
+		tm.wsMu.Lock()
+		tm.webSockets[ev.RequestID] = &webSocketInfo{URL: ev.URL}
+		tm.wsMu.Unlock()
+
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkWSOpen, &events.WebSocketOpenData{
+			RequestID: ev.RequestID.String(),
+			URL:       ev.URL,
+			Initiator: convertInitiator(ev.Initiator),
+		}))
+
+	case *network.EventWebSocketFrameSent:
+		tm.writeWebSocketFrame(ev.RequestID, ev.Response, true, site, tabID)
+
+	case *network.EventWebSocketFrameReceived:
+		tm.writeWebSocketFrame(ev.RequestID, ev.Response, false, site, tabID)
+
+	case *network.EventWebSocketClosed:
+		tm.wsMu.Lock()
+		info := tm.webSockets[ev.RequestID]
+		delete(tm.webSockets, ev.RequestID)
+		tm.wsMu.Unlock()
+
+		data := &events.WebSocketCloseData{RequestID: ev.RequestID.String()}
+		if info != nil {
+			data.URL = info.URL
+			data.FramesSent = info.FramesSent
+			data.FramesReceived = info.FramesReceived
+		}
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkWSClose, data))
+
+	case *network.EventWebSocketFrameError:
+		tm.wsMu.RLock()
+		var url string
+		if info := tm.webSockets[ev.RequestID]; info != nil {
+			url = info.URL
+		}
+		tm.wsMu.RUnlock()
+
+		tm.writeEvent(events.NewLogEvent(site, tabID, events.EventNetworkWSError, &events.WebSocketErrorData{
+			RequestID:    ev.RequestID.String(),
+			URL:          url,
+			ErrorMessage: ev.ErrorMessage,
+		}))
+	}
+}
+
+// writeWebSocketFrame logs a single sent or received frame. Like response
+// bodies, payloads are only logged with body capture on; text payloads are
+// redacted and truncated to the body size limit. Binary payloads can't be
+// redacted, so only their length is logged.
+func (tm *TabMonitor) writeWebSocketFrame(requestID network.RequestID, frame *network.WebSocketFrame, sent bool, site, tabID string) {
+	if frame == nil {
+		return
+	}
+
+	// Sockets opened before monitoring started are not tracked: an entry
+	// created here would only be removed if the close event arrived, so
+	// their frames are logged without a URL or frame number
+	var frameNum int64
+	var url string
+	tm.wsMu.Lock()
+	if info, exists := tm.webSockets[requestID]; exists {
+		if sent {
+			info.FramesSent++
+			frameNum = info.FramesSent
+		} else {
+			info.FramesReceived++
+			frameNum = info.FramesReceived
+		}
+		url = info.URL
+	}
+	tm.wsMu.Unlock()
+
+	var payload string
+	var truncated bool
+	if tm.config.CaptureBodies && int64(frame.Opcode) == wsOpcodeText {
+		payload, truncated = truncatePayload(tm.redactor.RedactBody(frame.PayloadData), tm.config.BodySizeLimitKB*1024)
+	}
+
+	eventType := events.EventNetworkWSFrameReceived
+	if sent {
+		eventType = events.EventNetworkWSFrameSent
+	}
+
+	tm.writeEvent(events.NewLogEvent(site, tabID, eventType, &events.WebSocketFrameData{
+		RequestID:     requestID.String(),
+		URL:           url,
+		Frame:         frameNum,
+		Opcode:        int64(frame.Opcode),
+		Payload:       payload,
+		PayloadLength: len(frame.PayloadData),
+		Truncated:     truncated,
+	}))
+}
@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+)
+
+func TestWebSocketFrameCounting(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CaptureBodies = true
+	tm, dir := newTestMonitor(t, cfg)
+	id := network.RequestID("ws-1")
+
+	tm.handleEvent(&network.EventWebSocketCreated{RequestID: id, URL: "wss://example.com/live"})
+	tm.handleEvent(&network.EventWebSocketFrameSent{RequestID: id, Response: &network.WebSocketFrame{Opcode: 1, PayloadData: `{"op":"sub"}`}})
+	tm.handleEvent(&network.EventWebSocketFrameReceived{RequestID: id, Response: &network.WebSocketFrame{Opcode: 1, PayloadData: `{"token":"abc"}`}})
+	tm.handleEvent(&network.EventWebSocketFrameReceived{RequestID: id, Response: &network.WebSocketFrame{Opcode: 2, PayloadData: "AAEC"}})
+	tm.handleEvent(&network.EventWebSocketClosed{RequestID: id})
+
+	got := readTestEvents(t, tm, dir)
+	wantTypes := []string{
+		events.EventNetworkWSOpen,
+		events.EventNetworkWSFrameSent,
+		events.EventNetworkWSFrameReceived,
+		events.EventNetworkWSFrameReceived,
+		events.EventNetworkWSClose,
+	}
+	if len(got) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %d", len(wantTypes), len(got))
+	}
+	for i, want := range wantTypes {
+		if got[i]["event_type"] != want {
+			t.Errorf("event %d: expected %s, got %v", i, want, got[i]["event_type"])
+		}
+	}
+
+	received := got[3]["data"].(map[string]interface{})
+	if received["frame"] != float64(2) {
+		t.Errorf("expected second received frame to be numbered 2, got %v", received["frame"])
+	}
+	if received["url"] != "wss://example.com/live" {
+		t.Errorf("expected frame to carry socket URL, got %v", received["url"])
+	}
+
+	token := got[2]["data"].(map[string]interface{})
+	if strings.Contains(token["payload"].(string), "abc") {
+		t.Errorf("expected text frame payload to be redacted, got %v", token["payload"])
+	}
+
+	if binary := got[3]["data"].(map[string]interface{}); binary["payload"] != nil || binary["payload_length"] != float64(4) {
+		t.Errorf("expected binary frame payload to be omitted, got %v", binary)
+	}
+
+	closed := got[4]["data"].(map[string]interface{})
+	if closed["frames_sent"] != float64(1) || closed["frames_received"] != float64(2) {
+		t.Errorf("unexpected close frame counts: %v", closed)
+	}
+	if len(tm.webSockets) != 0 {
+		t.Errorf("expected socket to be forgotten after close, got %d tracked", len(tm.webSockets))
+	}
+}
+
+func TestWebSocketDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableWebSocket = false
+	tm, _ := newTestMonitor(t, cfg)
+
+	tm.handleEvent(&network.EventWebSocketCreated{RequestID: "ws-1", URL: "wss://example.com/live"})
+
+	if openTestFiles(tm) != 0 {
+		t.Error("expected no events to be written when WebSocket capture is disabled")
+	}
+}
+
+func TestWebSocketFrameFromUnknownSocket(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+
+	tm.handleEvent(&network.EventWebSocketFrameReceived{RequestID: "ws-old", Response: &network.WebSocketFrame{Opcode: 1, PayloadData: "hi"}})
+
+	if len(tm.webSockets) != 0 {
+		t.Errorf("expected frames not to track unknown sockets, got %d", len(tm.webSockets))
+	}
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(got))
+	}
+	if frame := got[0]["data"].(map[string]interface{}); frame["frame"] != nil || frame["payload_length"] != float64(2) {
+		t.Errorf("expected an unnumbered frame, got %v", frame)
+	}
+}
+
+func TestWebSocketPayloadRequiresBodyCapture(t *testing.T) {
+	tm, dir := newTestMonitor(t, config.DefaultConfig())
+	id := network.RequestID("ws-1")
+
+	tm.handleEvent(&network.EventWebSocketCreated{RequestID: id, URL: "wss://example.com/live"})
+	tm.handleEvent(&network.EventWebSocketFrameReceived{RequestID: id, Response: &network.WebSocketFrame{Opcode: 1, PayloadData: `{"msg":"hi"}`}})
+
+	got := readTestEvents(t, tm, dir)
+	frame := got[1]["data"].(map[string]interface{})
+	if frame["payload"] != nil {
+		t.Errorf("expected no payload without body capture, got %v", frame["payload"])
+	}
+	if frame["payload_length"] != float64(12) || frame["opcode"] != float64(1) {
+		t.Errorf("expected length and opcode to be logged, got %v", frame)
+	}
+}
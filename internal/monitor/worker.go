@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"context"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/logger"
+)
+
+// CDP target types for workers.
+const (
+	TargetTypeWorker        = "worker"
+	TargetTypeServiceWorker = "service_worker"
+	TargetTypeSharedWorker  = "shared_worker"
+)
+
+// workerIDLength is how much of a worker's target ID its log name keeps.
+const workerIDLength = 8
+
+// NewWorkerMonitor creates a monitor for a service or shared worker.
+// These workers can outlive the tabs that started them, so their events are
+// logged under the _workers site with one log per worker.
+func NewWorkerMonitor(
+	parentCtx context.Context,
+	targetID, workerType, url, sessionID string,
+	sink logger.Sink,
+	cfg *config.Config,
+) *TabMonitor {
+	tm := NewTabMonitor(parentCtx, targetID, workerLogID(url, targetID), logger.WorkersSite, "", url, sessionID, sink, cfg)
+	tm.origin = eventOrigin{WorkerID: targetID, WorkerType: workerType}
+	return tm
+}
+
+// workerLogID names a worker's log after its script's site and target ID, so
+// workers on the same site don't share, or close, each other's log.
+func workerLogID(url, targetID string) string {
+	id := targetID
+	if len(id) > workerIDLength {
+		id = id[:workerIDLength]
+	}
+	return logger.ExtractSite(url) + "_" + id
+}
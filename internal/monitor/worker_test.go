@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/target"
+
+	"github.com/ajsharma/browser_tail/internal/config"
+	"github.com/ajsharma/browser_tail/internal/events"
+	"github.com/ajsharma/browser_tail/internal/logger"
+)
+
+func TestChildWorkerLogsToParentTab(t *testing.T) {
+	cfg := config.DefaultConfig()
+	tm, dir := newTestMonitor(t, cfg)
+
+	child := NewTabMonitor(tm.ctx, "worker-target", tm.tabID, "", "", "https://example.com/worker.js", "", tm.sink, cfg)
+	child.parent = tm
+	child.origin = eventOrigin{WorkerID: "worker-target", WorkerType: TargetTypeWorker}
+	child.attached = true
+	tm.children[target.SessionID("session-1")] = child
+
+	child.handleEvent(&runtime.EventConsoleAPICalled{
+		Type: runtime.APITypeLog,
+		Args: []*runtime.RemoteObject{{Type: runtime.TypeString, Value: []byte(`"from worker"`)}},
+	})
+	tm.handleEvent(&target.EventDetachedFromTarget{SessionID: "session-1"})
+
+	if len(tm.children) != 0 {
+		t.Errorf("expected child to be removed on detach, got %d children", len(tm.children))
+	}
+	if child.ctx.Err() == nil {
+		t.Error("expected child context to be cancelled on detach")
+	}
+
+	got := readTestEvents(t, tm, dir)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events in tab log, got %d", len(got))
+	}
+
+	console := got[0]
+	if console["event_type"] != events.EventConsoleLog {
+		t.Errorf("expected %s, got %v", events.EventConsoleLog, console["event_type"])
+	}
+	if console["site"] != "example.com" || console["tab_id"] != "tab-1" {
+		t.Errorf("expected worker event under parent tab, got site=%v tab=%v", console["site"], console["tab_id"])
+	}
+	if console["worker_id"] != "worker-target" || console["worker_type"] != TargetTypeWorker {
+		t.Errorf("expected worker tags, got worker_id=%v worker_type=%v", console["worker_id"], console["worker_type"])
+	}
+
+	if got[1]["event_type"] != events.EventMetaWorkerDetached {
+		t.Errorf("expected %s, got %v", events.EventMetaWorkerDetached, got[1]["event_type"])
+	}
+
+	// Tab-level events must not carry worker tags
+	tm.writeEvent(events.NewPageLoadEvent("example.com", "tab-1", "https://example.com"))
+	got = readTestEvents(t, tm, dir)
+	if _, tagged := got[len(got)-1]["worker_id"]; tagged {
+		t.Error("expected tab event to have no worker_id")
+	}
+}
+
+func TestNewWorkerMonitor(t *testing.T) {
+	cfg := config.DefaultConfig()
+	fm := logger.NewFileManager(t.TempDir())
+
+	tm := NewWorkerMonitor(context.Background(), "sw-target", TargetTypeServiceWorker,
+		"https://app.example.com/sw.js", "session-123", fm, cfg)
+
+	if site := tm.CurrentSite(); site != logger.WorkersSite {
+		t.Errorf("expected site %s, got %s", logger.WorkersSite, site)
+	}
+	if tabID := tm.TabID(); tabID != "app.example.com_sw-targe" {
+		t.Errorf("expected log app.example.com_sw-targe, got %s", tabID)
+	}
+	if tm.origin.WorkerID != "sw-target" || tm.origin.WorkerType != TargetTypeServiceWorker {
+		t.Errorf("unexpected origin: %+v", tm.origin)
+	}
+
+	// Stopping before attaching writes nothing and is idempotent
+	tm.Stop()
+	tm.Stop()
+	if fm.GetOpenFiles() != 0 {
+		t.Errorf("expected no open files, got %d", fm.GetOpenFiles())
+	}
+}
+
+func TestWorkerLogsAreSeparate(t *testing.T) {
+	cfg := config.DefaultConfig()
+	dir := t.TempDir()
+	fm := logger.NewFileManager(dir)
+	defer fm.Close()
+
+	first := NewWorkerMonitor(context.Background(), "AAAAAAAA1111", TargetTypeServiceWorker,
+		"https://example.com/sw.js", "", fm, cfg)
+	second := NewWorkerMonitor(context.Background(), "BBBBBBBB2222", TargetTypeSharedWorker,
+		"https://example.com/shared.js", "", fm, cfg)
+	if first.TabID() == second.TabID() {
+		t.Fatalf("expected separate logs, both use %s", first.TabID())
+	}
+
+	first.writeEvent(events.NewLogEvent(logger.WorkersSite, first.TabID(), events.EventConsoleLog, nil))
+	second.writeEvent(events.NewLogEvent(logger.WorkersSite, second.TabID(), events.EventConsoleLog, nil))
+
+	// Stopping one worker leaves the other's log open
+	first.attached = true
+	first.Stop()
+	if fm.GetOpenFiles() != 1 {
+		t.Errorf("expected the other worker's log to stay open, got %d open", fm.GetOpenFiles())
+	}
+}
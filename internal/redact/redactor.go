@@ -2,7 +2,12 @@
 package redact
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 // RedactedValue is the placeholder for redacted content.
@@ -85,6 +90,73 @@ func (r *Redactor) RedactBody(body string) string {
 	return string(result)
 }
 
+// RedactFormBody redacts sensitive fields from an
+// application/x-www-form-urlencoded body.
+// Malformed bodies are still redacted field by field: names that can't be
+// decoded are matched by their raw and partly decoded forms.
+func (r *Redactor) RedactFormBody(body string) string {
+	if !r.enabled || body == "" {
+		return body
+	}
+
+	// Rewrite fields in place to preserve the original field order.
+	parts := strings.Split(body, "&")
+	for i, part := range parts {
+		name, _, _ := strings.Cut(part, "=")
+		if r.shouldRedactBodyField(name) || r.shouldRedactBodyField(unescapeFormName(name)) {
+			parts[i] = name + "=" + url.QueryEscape(RedactedValue)
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// unescapeFormName decodes a form field name, leaving invalid escapes as
+// they are instead of failing.
+func unescapeFormName(name string) string {
+	if key, err := url.QueryUnescape(name); err == nil {
+		return key
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case c == '+':
+			b.WriteByte(' ')
+		case c == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]):
+			v, _ := strconv.ParseUint(name[i+1:i+3], 16, 8)
+			b.WriteByte(byte(v))
+			i += 2
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// isHex reports whether c is a hexadecimal digit.
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// RedactValue replaces a cookie or storage value with a short fingerprint.
+// Cookies usually carry session identifiers and apps keep tokens in storage
+// under all kinds of keys, so values are never logged, but the fingerprint
+// still shows when a value changes (or doesn't).
+func (r *Redactor) RedactValue(value string) string {
+	if !r.enabled || value == "" {
+		return value
+	}
+	return fingerprint(value)
+}
+
+// fingerprint returns a redaction placeholder identifying value by a
+// truncated SHA-256 hash.
+func fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "[REDACTED sha256:" + hex.EncodeToString(sum[:4]) + "]"
+}
+
 // shouldRedactHeader checks if a header should be redacted.
 func (r *Redactor) shouldRedactHeader(name string) bool {
 	for _, pattern := range r.headerDenylist {
@@ -173,6 +173,77 @@ func TestRedactBodyDisabled(t *testing.T) {
 	}
 }
 
+func TestRedactFormBody(t *testing.T) {
+	r := New(true)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "redacts password field",
+			body:     "username=john&password=secret123",
+			expected: "username=john&password=%5BREDACTED%5D",
+		},
+		{
+			name:     "preserves field order",
+			body:     "b=2&api_key=abc&a=1",
+			expected: "b=2&api_key=%5BREDACTED%5D&a=1",
+		},
+		{
+			name:     "handles encoded field names",
+			body:     "user%5Bpassword%5D=secret",
+			expected: "user%5Bpassword%5D=%5BREDACTED%5D",
+		},
+		{
+			name:     "redacts fields in invalid encoding",
+			body:     "password=%zz&x=1",
+			expected: "password=%5BREDACTED%5D&x=1",
+		},
+		{
+			name:     "redacts field with invalid encoded name",
+			body:     "user%5Bpassword%5D%zz=secret&x=1",
+			expected: "user%5Bpassword%5D%zz=%5BREDACTED%5D&x=1",
+		},
+		{
+			name:     "handles empty body",
+			body:     "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := r.RedactFormBody(tt.body)
+			if result != tt.expected {
+				t.Errorf("RedactFormBody(%q) = %q, want %q", tt.body, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRedactValue(t *testing.T) {
+	r := New(true)
+
+	a := r.RedactValue("abc123")
+	if containsString(a, "abc123") || !containsString(a, "[REDACTED sha256:") {
+		t.Errorf("expected value to be fingerprinted, got %q", a)
+	}
+	if r.RedactValue("abc123") != a {
+		t.Error("expected equal values to have equal fingerprints")
+	}
+	if r.RedactValue("def456") == a {
+		t.Error("expected different values to have different fingerprints")
+	}
+	if got := r.RedactValue(""); got != "" {
+		t.Errorf("expected empty value to stay empty, got %q", got)
+	}
+	if New(false).RedactValue("abc123") != "abc123" {
+		t.Error("expected value to pass through when redaction is disabled")
+	}
+}
+
 func TestCustomRules(t *testing.T) {
 	r := NewWithCustomRules(true, []string{"x-custom-header"}, []string{"custom_field"})
 
@@ -0,0 +1,217 @@
+package sourcemap
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Fetcher retrieves the raw bytes of a script's source map.
+type Fetcher func() ([]byte, error)
+
+// ErrNotFound is returned, possibly wrapped, by a Fetcher when the map
+// doesn't exist. Unlike other fetch errors it is cached.
+var ErrNotFound = errors.New("source map not found")
+
+// Cache size limits.
+const (
+	// DefaultMaxBytes bounds the source map data a cache holds.
+	DefaultMaxBytes = 256 * 1024 * 1024
+
+	// minEntryBytes is what each entry counts for at least, so failures and
+	// tiny maps still bound the number of entries.
+	minEntryBytes = 1024
+)
+
+// Cache holds parsed source maps keyed by a hash of the generated script's
+// URL. It is safe for concurrent use and shared across tabs, so each map is
+// fetched and parsed once while it stays cached. The least recently used
+// maps are evicted once the cache holds more than DefaultMaxBytes.
+type Cache struct {
+	dir      string
+	maxBytes int
+	mu       sync.Mutex
+	entries  map[string]*entry
+	lru      *list.List // of *entry, most recently used first
+	bytes    int
+}
+
+// entry is a cached lookup. ready is closed once m or err is set.
+type entry struct {
+	key   string
+	ready chan struct{}
+	m     *Map
+	err   error
+	size  int
+	elem  *list.Element // nil while loading
+}
+
+// NewCache creates a cache. If dir is non-empty, maps are first looked up
+// there as "<script path>.map" or "<script file name>.map".
+func NewCache(dir string) *Cache {
+	return &Cache{
+		dir:      dir,
+		maxBytes: DefaultMaxBytes,
+		entries:  make(map[string]*entry),
+		lru:      list.New(),
+	}
+}
+
+// Get returns the source map for scriptURL, loading it from the local
+// directory or, failing that, from fetch (which may be nil). Maps that are
+// missing or invalid are cached as failures, so they are not requested
+// again. Other fetch errors, and a nil fetch for a script whose map isn't
+// known yet, are not cached, so a later call can still succeed.
+func (c *Cache) Get(scriptURL string, fetch Fetcher) (*Map, error) {
+	key := hashURL(scriptURL)
+
+	c.mu.Lock()
+	e, exists := c.entries[key]
+	if exists {
+		if e.elem != nil {
+			c.lru.MoveToFront(e.elem)
+		}
+	} else {
+		e = &entry{key: key, ready: make(chan struct{})}
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+
+	if exists {
+		<-e.ready
+		return e.m, e.err
+	}
+
+	m, size, keep, err := c.load(scriptURL, fetch)
+	e.m, e.err = m, err
+
+	c.mu.Lock()
+	if keep {
+		e.size = max(size, minEntryBytes)
+		e.elem = c.lru.PushFront(e)
+		c.bytes += e.size
+		c.evict()
+	} else {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+
+	close(e.ready)
+	return e.m, e.err
+}
+
+// evict removes the least recently used entries until the cache fits,
+// always keeping the newest one.
+func (c *Cache) evict() {
+	for c.bytes > c.maxBytes && c.lru.Len() > 1 {
+		e := c.lru.Remove(c.lru.Back()).(*entry)
+		delete(c.entries, e.key)
+		c.bytes -= e.size
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// load reads or fetches a script's map. It returns the size of the map data
+// and whether the result, map or failure, should be cached.
+func (c *Cache) load(scriptURL string, fetch Fetcher) (*Map, int, bool, error) {
+	if data, ok := c.readLocal(scriptURL); ok {
+		m, err := Parse(data)
+		return m, len(data), true, err
+	}
+	if fetch == nil {
+		return nil, 0, false, errors.New("no source map available")
+	}
+
+	data, err := fetch()
+	if err != nil {
+		return nil, 0, errors.Is(err, ErrNotFound), err
+	}
+	m, err := Parse(data)
+	return m, len(data), true, err
+}
+
+// readLocal looks for the script's map in the local directory.
+func (c *Cache) readLocal(scriptURL string) ([]byte, bool) {
+	if c.dir == "" {
+		return nil, false
+	}
+
+	u, err := url.Parse(scriptURL)
+	if err != nil || u.Path == "" {
+		return nil, false
+	}
+
+	// Cleaning a rooted path keeps candidates inside dir
+	p := path.Clean("/" + u.Path)
+	candidates := []string{
+		filepath.Join(c.dir, filepath.FromSlash(p)) + ".map",
+		filepath.Join(c.dir, path.Base(p)+".map"),
+	}
+
+	for _, candidate := range candidates {
+		if data, err := os.ReadFile(candidate); err == nil {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// ResolveURL resolves a sourceMappingURL relative to the script's URL.
+func ResolveURL(scriptURL, mapURL string) (string, error) {
+	ref, err := url.Parse(mapURL)
+	if err != nil {
+		return "", err
+	}
+	if ref.IsAbs() {
+		return mapURL, nil
+	}
+
+	base, err := url.Parse(scriptURL)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// DecodeDataURL returns the contents of an inline "data:" source map URL.
+func DecodeDataURL(dataURL string) ([]byte, error) {
+	rest, ok := strings.CutPrefix(dataURL, "data:")
+	if !ok {
+		return nil, fmt.Errorf("not a data URL")
+	}
+
+	meta, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return nil, fmt.Errorf("malformed data URL")
+	}
+
+	if strings.HasSuffix(meta, ";base64") {
+		return base64.StdEncoding.DecodeString(payload)
+	}
+
+	decoded, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(decoded), nil
+}
+
+func hashURL(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return hex.EncodeToString(sum[:])
+}
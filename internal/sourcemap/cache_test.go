@@ -0,0 +1,189 @@
+package sourcemap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheFetchesOnce(t *testing.T) {
+	c := NewCache("")
+	calls := 0
+	fetch := func() ([]byte, error) {
+		calls++
+		return []byte(testMap), nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get("https://example.com/main.js", fetch); err != nil {
+			t.Fatalf("Get error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 fetch, got %d", calls)
+	}
+}
+
+func TestCacheRemembersMissingMaps(t *testing.T) {
+	c := NewCache("")
+	calls := 0
+	fetch := func() ([]byte, error) {
+		calls++
+		return nil, fmt.Errorf("%w: main.js.map", ErrNotFound)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get("https://example.com/main.js", fetch); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected failed fetch to be cached, got %d calls", calls)
+	}
+}
+
+func TestCacheRetriesTransientFailures(t *testing.T) {
+	c := NewCache("")
+	calls := 0
+	fetch := func() ([]byte, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return []byte(testMap), nil
+	}
+
+	if _, err := c.Get("https://example.com/main.js", fetch); err == nil {
+		t.Fatal("expected the first fetch to fail")
+	}
+	if _, err := c.Get("https://example.com/main.js", fetch); err != nil {
+		t.Errorf("expected a retry to succeed, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 fetches, got %d", calls)
+	}
+}
+
+func TestCacheWithoutFetcherIsNotRemembered(t *testing.T) {
+	c := NewCache("")
+
+	// The script's map URL isn't known yet
+	if _, err := c.Get("https://example.com/main.js", nil); err == nil {
+		t.Fatal("expected error without a fetcher")
+	}
+	if c.Len() != 0 {
+		t.Errorf("expected nothing cached, got %d entries", c.Len())
+	}
+
+	fetch := func() ([]byte, error) { return []byte(testMap), nil }
+	if _, err := c.Get("https://example.com/main.js", fetch); err != nil {
+		t.Errorf("expected map once a fetcher is available, got %v", err)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewCache("")
+	c.maxBytes = 2 * minEntryBytes
+	calls := make(map[string]int)
+	get := func(name string) {
+		t.Helper()
+		fetch := func() ([]byte, error) {
+			calls[name]++
+			return []byte(testMap), nil
+		}
+		if _, err := c.Get("https://example.com/"+name, fetch); err != nil {
+			t.Fatalf("Get error: %v", err)
+		}
+	}
+
+	get("a.js")
+	get("b.js")
+	get("a.js") // a is now the most recently used
+	get("c.js") // evicts b
+
+	if c.Len() != 2 {
+		t.Errorf("expected 2 cached maps, got %d", c.Len())
+	}
+	get("a.js")
+	get("b.js")
+	if calls["a.js"] != 1 || calls["b.js"] != 2 {
+		t.Errorf("expected only b to be fetched again, got %v", calls)
+	}
+}
+
+func TestCacheLocalDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "static", "js"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "static", "js", "main.js.map"), []byte(testMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "vendor.js.map"), []byte(testMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCache(dir)
+	noFetch := func() ([]byte, error) {
+		t.Error("expected local map to be used instead of fetching")
+		return nil, errors.New("unexpected fetch")
+	}
+
+	if _, err := c.Get("https://example.com/static/js/main.js", noFetch); err != nil {
+		t.Errorf("expected map by script path, got %v", err)
+	}
+	if _, err := c.Get("https://cdn.example.com/lib/vendor.js", noFetch); err != nil {
+		t.Errorf("expected map by file name, got %v", err)
+	}
+	if _, err := c.Get("https://example.com/other.js", nil); err == nil {
+		t.Error("expected error for script without a local map")
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	tests := []struct {
+		script, mapURL, want string
+	}{
+		{"https://example.com/js/main.js", "main.js.map", "https://example.com/js/main.js.map"},
+		{"https://example.com/js/main.js", "/maps/main.js.map", "https://example.com/maps/main.js.map"},
+		{"https://example.com/js/main.js", "https://maps.example.com/main.js.map", "https://maps.example.com/main.js.map"},
+		{"https://example.com/js/main.js", "data:application/json;base64,e30=", "data:application/json;base64,e30="},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolveURL(tt.script, tt.mapURL)
+		if err != nil {
+			t.Fatalf("ResolveURL error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("ResolveURL(%q, %q) = %q, want %q", tt.script, tt.mapURL, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeDataURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"data:application/json;base64,eyJ2ZXJzaW9uIjozfQ==", `{"version":3}`},
+		{"data:application/json;charset=utf-8;base64,e30=", `{}`},
+		{"data:application/json,%7B%7D", `{}`},
+	}
+
+	for _, tt := range tests {
+		got, err := DecodeDataURL(tt.url)
+		if err != nil {
+			t.Fatalf("DecodeDataURL(%q) error: %v", tt.url, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("DecodeDataURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+
+	if _, err := DecodeDataURL("https://example.com/x.map"); err == nil {
+		t.Error("expected error for non-data URL")
+	}
+}
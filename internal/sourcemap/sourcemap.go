@@ -0,0 +1,258 @@
+// Package sourcemap parses source map v3 files and maps generated
+// positions back to their original source locations.
+package sourcemap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Position is an original source location. Line and Column are 0-based.
+// Name is the original identifier mapped at that location, if any.
+type Position struct {
+	Source string
+	Line   int
+	Column int
+	Name   string
+}
+
+// Map is a parsed source map.
+type Map struct {
+	sources  []string
+	names    []string
+	lines    [][]segment
+	sections []section
+}
+
+// segment is a single decoded mapping within a generated line.
+type segment struct {
+	genColumn int
+	source    int
+	line      int
+	column    int
+	name      int
+}
+
+// section is one part of an index map, offset within the generated file.
+type section struct {
+	line   int
+	column int
+	m      *Map
+}
+
+// rawMap is the JSON form of a source map, including index maps.
+type rawMap struct {
+	Version    int          `json:"version"`
+	SourceRoot string       `json:"sourceRoot"`
+	Sources    []string     `json:"sources"`
+	Names      []string     `json:"names"`
+	Mappings   string       `json:"mappings"`
+	Sections   []rawSection `json:"sections"`
+}
+
+type rawSection struct {
+	Offset struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"offset"`
+	Map *rawMap `json:"map"`
+}
+
+// Parse parses a source map. Both regular and index (sectioned) maps are
+// supported; sections referenced by URL are not.
+func Parse(data []byte) (*Map, error) {
+	// Maps may be prefixed with an XSSI guard line
+	if s := string(data); strings.HasPrefix(s, ")]}") {
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+
+	var raw rawMap
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid source map: %w", err)
+	}
+	return fromRaw(&raw)
+}
+
+func fromRaw(raw *rawMap) (*Map, error) {
+	if raw.Version != 3 {
+		return nil, fmt.Errorf("unsupported source map version %d", raw.Version)
+	}
+
+	if len(raw.Sections) > 0 {
+		m := &Map{}
+		for _, s := range raw.Sections {
+			if s.Map == nil {
+				return nil, errors.New("index map section without inline map")
+			}
+			sub, err := fromRaw(s.Map)
+			if err != nil {
+				return nil, err
+			}
+			m.sections = append(m.sections, section{line: s.Offset.Line, column: s.Offset.Column, m: sub})
+		}
+		return m, nil
+	}
+
+	lines, err := decodeMappings(raw.Mappings, len(raw.Sources), len(raw.Names))
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]string, len(raw.Sources))
+	for i, src := range raw.Sources {
+		sources[i] = joinSourceRoot(raw.SourceRoot, src)
+	}
+
+	return &Map{sources: sources, names: raw.Names, lines: lines}, nil
+}
+
+// Lookup returns the original position for a 0-based generated line and
+// column. It reports false if the position is not mapped.
+func (m *Map) Lookup(line, column int) (Position, bool) {
+	if len(m.sections) > 0 {
+		return m.lookupSection(line, column)
+	}
+
+	if line < 0 || line >= len(m.lines) {
+		return Position{}, false
+	}
+	segs := m.lines[line]
+
+	// Find the last segment starting at or before column
+	i := sort.Search(len(segs), func(i int) bool { return segs[i].genColumn > column }) - 1
+	if i < 0 || segs[i].source < 0 {
+		return Position{}, false
+	}
+
+	seg := segs[i]
+	pos := Position{Source: m.sources[seg.source], Line: seg.line, Column: seg.column}
+	if seg.name >= 0 {
+		pos.Name = m.names[seg.name]
+	}
+	return pos, true
+}
+
+func (m *Map) lookupSection(line, column int) (Position, bool) {
+	// Sections are ordered; use the last one starting at or before the position
+	i := sort.Search(len(m.sections), func(i int) bool {
+		s := m.sections[i]
+		return s.line > line || (s.line == line && s.column > column)
+	}) - 1
+	if i < 0 {
+		return Position{}, false
+	}
+
+	s := m.sections[i]
+	if line == s.line {
+		column -= s.column
+	}
+	return s.m.Lookup(line-s.line, column)
+}
+
+// decodeMappings decodes the VLQ "mappings" field into segments per line.
+func decodeMappings(mappings string, numSources, numNames int) ([][]segment, error) {
+	var (
+		lines                      [][]segment
+		current                    []segment
+		source, line, column, name int
+	)
+
+	for _, group := range strings.Split(mappings, ";") {
+		current = nil
+		genColumn := 0
+		for _, field := range strings.Split(group, ",") {
+			if field == "" {
+				continue
+			}
+			values, err := decodeVLQ(field)
+			if err != nil {
+				return nil, err
+			}
+
+			genColumn += values[0]
+			seg := segment{genColumn: genColumn, source: -1, name: -1}
+			switch len(values) {
+			case 1:
+			case 4, 5:
+				source += values[1]
+				line += values[2]
+				column += values[3]
+				if source < 0 || source >= numSources {
+					return nil, fmt.Errorf("mapping references unknown source %d", source)
+				}
+				seg.source, seg.line, seg.column = source, line, column
+				if len(values) == 5 {
+					name += values[4]
+					if name < 0 || name >= numNames {
+						return nil, fmt.Errorf("mapping references unknown name %d", name)
+					}
+					seg.name = name
+				}
+			default:
+				return nil, fmt.Errorf("invalid mapping segment %q", field)
+			}
+			current = append(current, seg)
+		}
+
+		sort.SliceStable(current, func(i, j int) bool { return current[i].genColumn < current[j].genColumn })
+		lines = append(lines, current)
+	}
+
+	return lines, nil
+}
+
+const base64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// decodeVLQ decodes a base64 VLQ field into its signed values.
+func decodeVLQ(field string) ([]int, error) {
+	var (
+		values []int
+		value  int
+		shift  uint
+	)
+
+	for i := 0; i < len(field); i++ {
+		digit := strings.IndexByte(base64Chars, field[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base64 VLQ character %q", field[i])
+		}
+
+		value += (digit & 31) << shift
+		if digit&32 != 0 {
+			shift += 5
+			if shift > 30 {
+				return nil, errors.New("VLQ value overflows")
+			}
+			continue
+		}
+
+		// The lowest bit holds the sign
+		if value&1 != 0 {
+			values = append(values, -(value >> 1))
+		} else {
+			values = append(values, value>>1)
+		}
+		value, shift = 0, 0
+	}
+
+	if shift != 0 {
+		return nil, errors.New("truncated VLQ value")
+	}
+	return values, nil
+}
+
+// joinSourceRoot prefixes a source path with the map's sourceRoot.
+func joinSourceRoot(root, source string) string {
+	if root == "" || strings.Contains(source, "://") || strings.HasPrefix(source, "/") {
+		return source
+	}
+	if !strings.HasSuffix(root, "/") {
+		root += "/"
+	}
+	return root + source
+}
@@ -0,0 +1,125 @@
+package sourcemap
+
+import (
+	"testing"
+)
+
+const testMap = `{
+	"version": 3,
+	"sourceRoot": "webpack:///",
+	"sources": ["src/a.js", "src/b.js"],
+	"names": ["handleClick"],
+	"mappings": "AAAA,IAAIA;ACCA"
+}`
+
+func TestDecodeVLQ(t *testing.T) {
+	tests := []struct {
+		field string
+		want  []int
+	}{
+		{"A", []int{0}},
+		{"C", []int{1}},
+		{"D", []int{-1}},
+		{"gB", []int{16}},
+		{"AAIA", []int{0, 0, 4, 0}},
+	}
+
+	for _, tt := range tests {
+		got, err := decodeVLQ(tt.field)
+		if err != nil {
+			t.Fatalf("decodeVLQ(%q) error: %v", tt.field, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("decodeVLQ(%q) = %v, want %v", tt.field, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("decodeVLQ(%q) = %v, want %v", tt.field, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if _, err := decodeVLQ("g"); err == nil {
+		t.Error("expected error for truncated VLQ value")
+	}
+	if _, err := decodeVLQ("!"); err == nil {
+		t.Error("expected error for invalid character")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	m, err := Parse([]byte(testMap))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		line, column int
+		want         Position
+		wantOK       bool
+	}{
+		{"start of line", 0, 0, Position{Source: "webpack:///src/a.js"}, true},
+		{"between segments", 0, 2, Position{Source: "webpack:///src/a.js"}, true},
+		{"named segment", 0, 10, Position{Source: "webpack:///src/a.js", Column: 4, Name: "handleClick"}, true},
+		{"second source", 1, 5, Position{Source: "webpack:///src/b.js", Line: 1, Column: 4}, true},
+		{"unmapped line", 2, 0, Position{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.Lookup(tt.line, tt.column)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Lookup(%d, %d) = %+v, %v; want %+v, %v", tt.line, tt.column, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLookupIndexMap(t *testing.T) {
+	data := `{
+		"version": 3,
+		"sections": [
+			{"offset": {"line": 0, "column": 0}, "map": {"version": 3, "sources": ["a.js"], "names": [], "mappings": "AAAA"}},
+			{"offset": {"line": 0, "column": 100}, "map": {"version": 3, "sources": ["b.js"], "names": [], "mappings": "AACA"}}
+		]
+	}`
+
+	m, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	if got, ok := m.Lookup(0, 50); !ok || got.Source != "a.js" {
+		t.Errorf("expected a.js for column 50, got %+v, %v", got, ok)
+	}
+	if got, ok := m.Lookup(0, 120); !ok || got.Source != "b.js" || got.Line != 1 {
+		t.Errorf("expected b.js line 1 for column 120, got %+v, %v", got, ok)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"invalid JSON", `not json`},
+		{"wrong version", `{"version": 2, "sources": [], "mappings": ""}`},
+		{"unknown source", `{"version": 3, "sources": [], "mappings": "AAAA"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseXSSIPrefix(t *testing.T) {
+	if _, err := Parse([]byte(")]}'\n" + testMap)); err != nil {
+		t.Errorf("expected XSSI-prefixed map to parse, got %v", err)
+	}
+}